@@ -0,0 +1,123 @@
+package rtree
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrMaxResults = errors.New("rtree: max results exceeded")
+	ErrMaxNodes   = errors.New("rtree: max nodes visited exceeded")
+)
+
+// SearchOptions bounds the work done by context-aware searches. Zero values
+// mean no limit.
+type SearchOptions struct {
+	MaxResults int
+	MaxNodes   int
+}
+
+// AbortError is returned with the partial results of a search stopped
+// before completion. Err is the context error, ErrMaxResults or ErrMaxNodes.
+type AbortError struct {
+	Err     error
+	Visited int
+}
+
+func (e *AbortError) Error() string {
+	return fmt.Sprintf("%s after visiting %d nodes", e.Err, e.Visited)
+}
+
+func (e *AbortError) Unwrap() error {
+	return e.Err
+}
+
+// SearchContext is like Search but gives up when ctx is done or when one of
+// the limits in opts is exceeded, returning the features found so far and
+// an *AbortError.
+func (t *Rtree) SearchContext(ctx context.Context, mbr Mbr, opts SearchOptions) ([]Feature, error) {
+	return t.QueryContext(ctx, &windowQuery{mbr}, opts)
+}
+
+// QueryContext is like Query but gives up when ctx is done or when one of
+// the limits in opts is exceeded, returning the features found so far and
+// an *AbortError.
+func (t *Rtree) QueryContext(ctx context.Context, q Query, opts SearchOptions) ([]Feature, error) {
+	s := &contextSearch{
+		ctx:     ctx,
+		q:       q,
+		opts:    opts,
+		results: []Feature{},
+	}
+
+	if err := s.search(t.root); err != nil {
+		return s.results, &AbortError{
+			Err:     err,
+			Visited: s.visited,
+		}
+	}
+
+	return s.results, nil
+}
+
+type contextSearch struct {
+	ctx     context.Context
+	q       Query
+	opts    SearchOptions
+	results []Feature
+	visited int
+}
+
+func (s *contextSearch) search(n *node) error {
+	select {
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	default:
+	}
+
+	if s.opts.MaxNodes > 0 && s.visited >= s.opts.MaxNodes {
+		return ErrMaxNodes
+	}
+	s.visited++
+
+	for _, e := range n.objs {
+		if !n.leaf {
+			if !s.q.MatchNode(e.mbr) {
+				continue
+			}
+
+			if err := s.search(e.node); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !s.q.MatchNode(e.mbr) || !s.q.MatchFeature(e.feature) {
+			continue
+		}
+
+		if s.opts.MaxResults > 0 && len(s.results) >= s.opts.MaxResults {
+			return ErrMaxResults
+		}
+
+		s.results = append(s.results, e.feature)
+	}
+
+	return nil
+}
+
+// windowQuery matches features intersecting an mbr, as Search does.
+type windowQuery struct {
+	mbr Mbr
+}
+
+func (q *windowQuery) MatchNode(mbr Mbr) bool {
+	return q.mbr.Intersects(mbr)
+}
+
+// MatchFeature accepts every feature, leaf entries were already tested by
+// MatchNode against their stored mbr.
+func (q *windowQuery) MatchFeature(feature Feature) bool {
+	return true
+}
@@ -0,0 +1,77 @@
+package rtree
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func Test_SearchContext(t *testing.T) {
+	features := randomRects(3000, 1000, 50)
+	tree := NewRtree(2, 16, features...)
+
+	window := NewMbrInt32([]int32{0, 0}, []int32{500, 500})
+	expected := len(tree.Search(window))
+
+	result, err := tree.SearchContext(context.Background(), window, SearchOptions{})
+	if err != nil || len(result) != expected {
+		t.Errorf("SearchContext() got %d results and %v, want %d", len(result), err, expected)
+	}
+
+	result, err = tree.SearchContext(context.Background(), window, SearchOptions{MaxResults: 10})
+	if !errors.Is(err, ErrMaxResults) || len(result) != 10 {
+		t.Errorf("SearchContext() got %d results and %v, want 10 and ErrMaxResults", len(result), err)
+	}
+
+	_, err = tree.SearchContext(context.Background(), window, SearchOptions{MaxNodes: 3})
+	var abort *AbortError
+	if !errors.As(err, &abort) || !errors.Is(err, ErrMaxNodes) || abort.Visited != 3 {
+		t.Errorf("SearchContext() got %v, want ErrMaxNodes after 3 nodes", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err = tree.SearchContext(ctx, window, SearchOptions{})
+	if !errors.Is(err, context.Canceled) || len(result) != 0 {
+		t.Errorf("SearchContext() got %d results and %v, want context.Canceled", len(result), err)
+	}
+}
+
+// countingRect counts the calls to Mbr.
+type countingRect struct {
+	Rect
+	calls *int
+}
+
+func (r *countingRect) Mbr() Mbr {
+	*r.calls++
+	return r.Rect.Mbr()
+}
+
+func Test_SearchContext_StoredMbr(t *testing.T) {
+	calls := 0
+	features := []Feature{}
+	for _, f := range randomRects(2000, 1000, 50) {
+		features = append(features, &countingRect{*f.(*Rect), &calls})
+	}
+
+	tree := NewRtree(2, 16, features...)
+	window := NewMbrInt32([]int32{200, 200}, []int32{400, 400})
+	expected := len(tree.Search(window))
+
+	// Window searches test leaves against their stored mbrs, like Search.
+	calls = 0
+	result, err := tree.SearchContext(context.Background(), window, SearchOptions{})
+	if err != nil || len(result) != expected {
+		t.Errorf("SearchContext() got %d results and %v, want %d", len(result), err, expected)
+	}
+
+	if count := len(tree.Query(&windowQuery{window})); count != expected {
+		t.Errorf("Query() got %d results, want %d", count, expected)
+	}
+
+	if calls != 0 {
+		t.Errorf("window searches called Mbr() %d times, want 0", calls)
+	}
+}
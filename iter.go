@@ -0,0 +1,36 @@
+package rtree
+
+import (
+	"iter"
+)
+
+// SearchSeq returns an iterator over the features intersecting mbr.
+func (t *Rtree) SearchSeq(mbr Mbr) iter.Seq[Feature] {
+	return func(yield func(Feature) bool) {
+		t.searchFunc(t.root, mbr, yield)
+	}
+}
+
+// All returns an iterator over every feature in the tree.
+func (t *Rtree) All() iter.Seq[Feature] {
+	return func(yield func(Feature) bool) {
+		t.walk(t.root, yield)
+	}
+}
+
+func (t *Rtree) walk(n *node, fn func(Feature) bool) bool {
+	for _, e := range n.objs {
+		if !n.leaf {
+			if !t.walk(e.node, fn) {
+				return false
+			}
+			continue
+		}
+
+		if !fn(e.feature) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,50 @@
+package rtree
+
+import (
+	"testing"
+)
+
+func Test_All(t *testing.T) {
+	features := randomRects(1000, 1000, 50)
+	tree := NewRtree(2, 16, features...)
+
+	seen := map[int]bool{}
+	for f := range tree.All() {
+		seen[f.(*Rect).id] = true
+	}
+
+	if len(seen) != len(features) {
+		t.Errorf("All() visited %d features, want %d", len(seen), len(features))
+	}
+}
+
+func Test_SearchSeq(t *testing.T) {
+	features := randomRects(1000, 1000, 50)
+	tree := NewRtree(2, 16, features...)
+
+	window := NewMbrInt32([]int32{200, 200}, []int32{400, 400})
+
+	count := 0
+	for f := range tree.SearchSeq(window) {
+		if !window.Intersects(f.Mbr()) {
+			t.Errorf("SearchSeq() got wrong result: %s", f.Mbr())
+		}
+		count++
+	}
+
+	if expected := len(tree.Search(window)); count != expected {
+		t.Errorf("SearchSeq() visited %d features, want %d", count, expected)
+	}
+
+	count = 0
+	for range tree.SearchSeq(window) {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+
+	if count != 2 {
+		t.Errorf("SearchSeq() did not stop on break, visited %d features", count)
+	}
+}
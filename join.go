@@ -0,0 +1,169 @@
+package rtree
+
+import (
+	"container/heap"
+)
+
+// Join reports every pair of intersecting features from trees a and b by
+// traversing both trees together. Traversal stops as soon as fn returns
+// false.
+func Join(a, b *Rtree, fn func(fa, fb Feature) bool) {
+	joinNodes(a.root, b.root, intersects, fn)
+}
+
+// DistanceJoin reports every pair of features from trees a and b whose mbrs
+// are within dist of each other. Traversal stops as soon as fn returns false.
+func DistanceJoin(a, b *Rtree, dist float64, fn func(fa, fb Feature) bool) {
+	within := func(ma, mb Mbr) bool {
+		return minDist(ma, mb) <= dist
+	}
+
+	joinNodes(a.root, b.root, within, fn)
+}
+
+func intersects(a, b Mbr) bool {
+	return a.Intersects(b)
+}
+
+func joinNodes(na, nb *node, match func(a, b Mbr) bool, fn func(fa, fb Feature) bool) bool {
+	for _, ea := range na.objs {
+		for _, eb := range nb.objs {
+			if !match(ea.mbr, eb.mbr) {
+				continue
+			}
+
+			if !joinObjs(ea, eb, match, fn) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func joinObjs(ea, eb *object, match func(a, b Mbr) bool, fn func(fa, fb Feature) bool) bool {
+	switch {
+	case ea.node == nil && eb.node == nil:
+		return fn(ea.feature, eb.feature)
+	case ea.node == nil:
+		for _, e := range eb.node.objs {
+			if match(ea.mbr, e.mbr) && !joinObjs(ea, e, match, fn) {
+				return false
+			}
+		}
+		return true
+	case eb.node == nil:
+		for _, e := range ea.node.objs {
+			if match(e.mbr, eb.mbr) && !joinObjs(e, eb, match, fn) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return joinNodes(ea.node, eb.node, match, fn)
+}
+
+// SelfJoin reports each unordered pair of intersecting features in the tree
+// exactly once, never pairing a feature with itself. Traversal stops as soon
+// as fn returns false.
+func (t *Rtree) SelfJoin(fn func(a, b Feature) bool) {
+	selfJoinNode(t.root, fn)
+}
+
+func selfJoinNode(n *node, fn func(a, b Feature) bool) bool {
+	for i, ei := range n.objs {
+		if !n.leaf && !selfJoinNode(ei.node, fn) {
+			return false
+		}
+
+		for _, ej := range n.objs[i+1:] {
+			if !ei.mbr.Intersects(ej.mbr) {
+				continue
+			}
+
+			if !joinObjs(ei, ej, intersects, fn) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// ClosestPairs returns the k pairs of features from trees a and b with the
+// smallest distance between their mbrs, ordered by increasing distance,
+// together with their distances.
+func ClosestPairs(a, b *Rtree, k int) ([][2]Feature, []float64) {
+	pairs := [][2]Feature{}
+	dists := []float64{}
+
+	q := &pairQueue{}
+	q.pushPairs(a.root.objs, b.root.objs)
+
+	for q.Len() > 0 && len(pairs) < k {
+		item := heap.Pop(q).(*pairItem)
+		ea, eb := item.a, item.b
+
+		switch {
+		case ea.node == nil && eb.node == nil:
+			pairs = append(pairs, [2]Feature{ea.feature, eb.feature})
+			dists = append(dists, item.dist)
+		case ea.node == nil:
+			q.pushPairs([]*object{ea}, eb.node.objs)
+		case eb.node == nil:
+			q.pushPairs(ea.node.objs, []*object{eb})
+		case ea.node.level >= eb.node.level:
+			q.pushPairs(ea.node.objs, []*object{eb})
+		default:
+			q.pushPairs([]*object{ea}, eb.node.objs)
+		}
+	}
+
+	return pairs, dists
+}
+
+type pairItem struct {
+	a, b *object
+	dist float64
+}
+
+// pairQueue is a min-heap of object pairs ordered by their distance.
+type pairQueue []*pairItem
+
+func (q pairQueue) Len() int {
+	return len(q)
+}
+
+func (q pairQueue) Less(i, j int) bool {
+	return q[i].dist < q[j].dist
+}
+
+func (q pairQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *pairQueue) Push(x interface{}) {
+	*q = append(*q, x.(*pairItem))
+}
+
+func (q *pairQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return item
+}
+
+func (q *pairQueue) pushPairs(as, bs []*object) {
+	for _, ea := range as {
+		for _, eb := range bs {
+			heap.Push(q, &pairItem{
+				a:    ea,
+				b:    eb,
+				dist: minDist(ea.mbr, eb.mbr),
+			})
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package rtree
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func Test_Join(t *testing.T) {
+	as := randomRects(500, 1000, 50)
+	bs := randomRects(800, 1000, 30)
+
+	a := NewRtree(2, 8, as...)
+	b := NewRtree(2, 16)
+	for _, f := range bs {
+		b.Insert(f)
+	}
+
+	expected := 0
+	for _, fa := range as {
+		expected += len(b.Search(fa.Mbr()))
+	}
+
+	count := 0
+	Join(a, b, func(fa, fb Feature) bool {
+		if !fa.Mbr().Intersects(fb.Mbr()) {
+			t.Errorf("Join() got disjoint pair: %s, %s", fa.Mbr(), fb.Mbr())
+		}
+		count++
+		return true
+	})
+
+	if count != expected {
+		t.Errorf("Join() got %d pairs, want %d", count, expected)
+	}
+
+	count = 0
+	Join(a, b, func(fa, fb Feature) bool {
+		count++
+		return false
+	})
+
+	if count != 1 {
+		t.Errorf("Join() did not stop early, got %d pairs", count)
+	}
+}
+
+func Test_SelfJoin(t *testing.T) {
+	features := randomRects(1000, 1000, 40)
+	tree := NewRtree(2, 16)
+	for _, f := range features {
+		tree.Insert(f)
+	}
+
+	expected := 0
+	for i, fi := range features {
+		for _, fj := range features[i+1:] {
+			if fi.Mbr().Intersects(fj.Mbr()) {
+				expected++
+			}
+		}
+	}
+
+	seen := map[[2]int]bool{}
+	tree.SelfJoin(func(a, b Feature) bool {
+		ia, ib := a.(*Rect).id, b.(*Rect).id
+		if ia == ib {
+			t.Errorf("SelfJoin() got self pair: %d", ia)
+		}
+		if ia > ib {
+			ia, ib = ib, ia
+		}
+		if seen[[2]int{ia, ib}] {
+			t.Errorf("SelfJoin() got duplicated pair: %d, %d", ia, ib)
+		}
+		seen[[2]int{ia, ib}] = true
+		return true
+	})
+
+	if len(seen) != expected {
+		t.Errorf("SelfJoin() got %d pairs, want %d", len(seen), expected)
+	}
+}
+
+func Test_ClosestPairs(t *testing.T) {
+	as := []Feature{}
+	bs := []Feature{}
+	for i := 0; i < 300; i++ {
+		as = append(as, &FloatPoint{rand.Float64() * 1000, rand.Float64() * 1000, i})
+		bs = append(bs, &FloatPoint{rand.Float64() * 1000, rand.Float64() * 1000, i})
+	}
+
+	a := NewRtree(2, 8, as...)
+	b := NewRtree(2, 16, bs...)
+
+	expected := []float64{}
+	for _, fa := range as {
+		for _, fb := range bs {
+			pa, pb := fa.(*FloatPoint), fb.(*FloatPoint)
+			expected = append(expected, math.Hypot(pa.x-pb.x, pa.y-pb.y))
+		}
+	}
+	sort.Float64s(expected)
+
+	pairs, dists := ClosestPairs(a, b, 20)
+	if len(pairs) != 20 {
+		t.Fatalf("ClosestPairs() got %d pairs, want 20", len(pairs))
+	}
+
+	for i, d := range dists {
+		if math.Abs(d-expected[i]) > 1e-9 {
+			t.Errorf("ClosestPairs() got distance %f at %d, want %f", d, i, expected[i])
+		}
+	}
+
+	count := 0
+	DistanceJoin(a, b, dists[19], func(fa, fb Feature) bool {
+		if d := minDist(fa.Mbr(), fb.Mbr()); d > dists[19] {
+			t.Errorf("DistanceJoin() got pair at distance %f, want at most %f", d, dists[19])
+		}
+		count++
+		return true
+	})
+
+	if count < 20 {
+		t.Errorf("DistanceJoin() got %d pairs, want at least 20", count)
+	}
+}
@@ -2,6 +2,7 @@ package rtree
 
 import (
 	"fmt"
+	"math"
 	"strings"
 )
 
@@ -61,6 +62,8 @@ func NewMbrFloat64(mins []float64, spans []float64) *MbrFloat64 {
 type Mbr interface {
 	Type() int
 	Dim() int
+	Min(dim int) float64
+	Max(dim int) float64
 	Equals(m Mbr) bool
 	Contains(m Mbr) bool
 	Intersects(m Mbr) bool
@@ -79,6 +82,16 @@ func (m *MbrInt32) Dim() int {
 	return len(*m) / 2
 }
 
+// Min returns the lower bound of the mbr along dim.
+func (m *MbrInt32) Min(dim int) float64 {
+	return float64((*m)[dim*2])
+}
+
+// Max returns the upper bound of the mbr along dim.
+func (m *MbrInt32) Max(dim int) float64 {
+	return float64((*m)[dim*2]) + float64((*m)[dim*2+1])
+}
+
 func (a *MbrInt32) Equals(mbr Mbr) bool {
 	b, ok := mbr.(*MbrInt32)
 	if !ok {
@@ -194,6 +207,16 @@ func (m *MbrFloat64) Dim() int {
 	return len(m.mins)
 }
 
+// Min returns the lower bound of the mbr along dim.
+func (m *MbrFloat64) Min(dim int) float64 {
+	return m.mins[dim]
+}
+
+// Max returns the upper bound of the mbr along dim.
+func (m *MbrFloat64) Max(dim int) float64 {
+	return m.mins[dim] + m.spans[dim]
+}
+
 func (a *MbrFloat64) Equals(mbr Mbr) bool {
 	b, ok := mbr.(*MbrFloat64)
 	if !ok {
@@ -299,6 +322,28 @@ func (m *MbrFloat64) size() float64 {
 	return size
 }
 
+// minDist returns the minimum Euclidean distance between two mbrs,
+// 0 if they intersect. Only the dimensions shared by both are considered.
+func minDist(a, b Mbr) float64 {
+	dim := a.Dim()
+	if b.Dim() < dim {
+		dim = b.Dim()
+	}
+
+	var sum float64
+	for i := 0; i < dim; i++ {
+		var d float64
+		if a.Max(i) < b.Min(i) {
+			d = b.Min(i) - a.Max(i)
+		} else if b.Max(i) < a.Min(i) {
+			d = a.Min(i) - b.Max(i)
+		}
+		sum += d * d
+	}
+
+	return math.Sqrt(sum)
+}
+
 func MergeMbrs(mbrs ...Mbr) Mbr {
 	mbrLen := len(mbrs)
 	if mbrLen == 0 {
@@ -382,3 +427,105 @@ func mergeFloat64Mbrs(mbrs ...Mbr) Mbr {
 
 	return mbr
 }
+
+// overlapSize returns the size of the intersection of a and b.
+func overlapSize(a, b Mbr) float64 {
+	size := 1.0
+
+	for i := 0; i < a.Dim() && i < b.Dim(); i++ {
+		d := math.Min(a.Max(i), b.Max(i)) - math.Max(a.Min(i), b.Min(i))
+		if d <= 0 {
+			return 0
+		}
+		size *= d
+	}
+
+	return size
+}
+
+// marginOf returns the sum of the edge lengths of m.
+func marginOf(m Mbr) float64 {
+	var margin float64
+
+	for i := 0; i < m.Dim(); i++ {
+		margin += m.Max(i) - m.Min(i)
+	}
+
+	return margin
+}
+
+// maxDist returns the maximum Euclidean distance between any point of a and
+// any point of b.
+func maxDist(a, b Mbr) float64 {
+	dim := a.Dim()
+	if b.Dim() < dim {
+		dim = b.Dim()
+	}
+
+	var sum float64
+	for i := 0; i < dim; i++ {
+		d := math.Max(a.Max(i)-b.Min(i), b.Max(i)-a.Min(i))
+		sum += d * d
+	}
+
+	return math.Sqrt(sum)
+}
+
+// pointDists returns the minimum and maximum Euclidean distances from point
+// to any point of m.
+func pointDists(m Mbr, point []float64) (float64, float64) {
+	var near, far float64
+
+	for i := 0; i < m.Dim() && i < len(point); i++ {
+		lo, hi := point[i]-m.Min(i), m.Max(i)-point[i]
+
+		if lo < 0 {
+			near += lo * lo
+		} else if hi < 0 {
+			near += hi * hi
+		}
+
+		if lo > hi {
+			far += lo * lo
+		} else {
+			far += hi * hi
+		}
+	}
+
+	return math.Sqrt(near), math.Sqrt(far)
+}
+
+// rayIntersect clips the segment origin + t*dir, 0 <= t <= maxT, against m
+// with the slab method and returns the entry parameter t.
+func rayIntersect(m Mbr, origin, dir []float64, maxT float64) (float64, bool) {
+	tmin, tmax := 0.0, maxT
+
+	for i := 0; i < m.Dim() && i < len(origin); i++ {
+		lo, hi := m.Min(i), m.Max(i)
+
+		if dir[i] == 0 {
+			if origin[i] < lo || origin[i] > hi {
+				return 0, false
+			}
+			continue
+		}
+
+		t1 := (lo - origin[i]) / dir[i]
+		t2 := (hi - origin[i]) / dir[i]
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+
+		if t1 > tmin {
+			tmin = t1
+		}
+		if t2 < tmax {
+			tmax = t2
+		}
+		if tmin > tmax {
+			return 0, false
+		}
+	}
+
+	return tmin, true
+}
@@ -0,0 +1,200 @@
+package rtree
+
+import (
+	"container/heap"
+)
+
+// Nearest returns the k features closest to point, ordered by increasing
+// distance, together with their distances. The point is an Mbr, usually
+// with zero spans, and distances are measured between mbrs.
+func (t *Rtree) Nearest(point Mbr, k int) ([]Feature, []float64) {
+	features := []Feature{}
+	dists := []float64{}
+
+	it := t.NearestIterator(point)
+	for len(features) < k {
+		feature, dist, ok := it.Next()
+		if !ok {
+			break
+		}
+
+		features = append(features, feature)
+		dists = append(dists, dist)
+	}
+
+	return features, dists
+}
+
+// NearestIterator browses the features of a tree in increasing distance
+// from a query mbr. Features are produced lazily, so callers may stop at
+// any time. The tree must not be modified while iterating.
+type NearestIterator struct {
+	query Mbr
+	queue *distQueue
+}
+
+// NearestIterator returns an iterator over all features ordered by
+// increasing distance from query.
+func (t *Rtree) NearestIterator(query Mbr) *NearestIterator {
+	it := &NearestIterator{
+		query: query,
+		queue: &distQueue{},
+	}
+
+	it.queue.pushNode(t.root, query)
+
+	return it
+}
+
+// Next returns the next closest feature and its distance, ok is false once
+// all features have been visited.
+func (it *NearestIterator) Next() (feature Feature, dist float64, ok bool) {
+	for it.queue.Len() > 0 {
+		item := heap.Pop(it.queue).(*distItem)
+
+		if item.obj.node != nil {
+			it.queue.pushNode(item.obj.node, it.query)
+			continue
+		}
+
+		return item.obj.feature, item.dist, true
+	}
+
+	return nil, 0, false
+}
+
+type distItem struct {
+	obj  *object
+	dist float64
+}
+
+// distQueue is a min-heap of objects ordered by their distance to a query.
+type distQueue []*distItem
+
+func (q distQueue) Len() int {
+	return len(q)
+}
+
+func (q distQueue) Less(i, j int) bool {
+	return q[i].dist < q[j].dist
+}
+
+func (q distQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *distQueue) Push(x interface{}) {
+	*q = append(*q, x.(*distItem))
+}
+
+func (q *distQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return item
+}
+
+func (q *distQueue) pushNode(n *node, point Mbr) {
+	for _, e := range n.objs {
+		heap.Push(q, &distItem{
+			obj:  e,
+			dist: minDist(point, e.mbr),
+		})
+	}
+}
+
+// ReverseNearest returns the features that would have point among their k
+// nearest neighbors, that is the features with fewer than k other features
+// strictly closer to them than point.
+func (t *Rtree) ReverseNearest(point Mbr, k int) []Feature {
+	results := []Feature{}
+	if k <= 0 {
+		return results
+	}
+
+	// Features are browsed from point outwards, the ones already seen are
+	// indexed and used to prune subtrees whose features all have k closer
+	// neighbors. Only seen features within d of an mbr can be closer than
+	// point to any part of it.
+	seen := NewRtree(t.dim, t.fan)
+	dominated := func(mbr Mbr) bool {
+		d := minDist(mbr, point)
+		count := 0
+		seen.searchWithinFunc(seen.root, mbr, d, func(e *object) bool {
+			if maxDist(mbr, e.mbr) < d {
+				count++
+			}
+			return count < k
+		})
+		return count >= k
+	}
+
+	q := &distQueue{}
+	q.pushNode(t.root, point)
+
+	for q.Len() > 0 {
+		item := heap.Pop(q).(*distItem)
+
+		if item.obj.node != nil {
+			if !dominated(item.obj.mbr) {
+				q.pushNode(item.obj.node, point)
+			}
+			continue
+		}
+
+		feature := item.obj.feature
+		if !dominated(item.obj.mbr) && t.closerThan(feature, item.dist, k) < k {
+			results = append(results, feature)
+		}
+
+		seen.insertObj(&object{mbr: item.obj.mbr, feature: feature}, 1)
+	}
+
+	return results
+}
+
+// closerThan counts the other features strictly closer to feature than dist,
+// counting at most k of them.
+func (t *Rtree) closerThan(feature Feature, dist float64, k int) int {
+	count := 0
+
+	it := t.NearestIterator(feature.Mbr())
+	for {
+		f, d, ok := it.Next()
+		if !ok || d >= dist {
+			return count
+		}
+
+		if !f.Equals(feature) {
+			count++
+			if count >= k {
+				return count
+			}
+		}
+	}
+}
+
+// searchWithinFunc calls fn for the leaf entries within radius of center,
+// until fn returns false.
+func (t *Rtree) searchWithinFunc(n *node, center Mbr, radius float64, fn func(*object) bool) bool {
+	for _, e := range n.objs {
+		if minDist(center, e.mbr) > radius {
+			continue
+		}
+
+		if !n.leaf {
+			if !t.searchWithinFunc(e.node, center, radius, fn) {
+				return false
+			}
+			continue
+		}
+
+		if !fn(e) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,160 @@
+package rtree
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+type FloatPoint struct {
+	x  float64
+	y  float64
+	id int
+}
+
+func (p *FloatPoint) Mbr() Mbr {
+	return NewMbrFloat64([]float64{p.x, p.y}, []float64{0, 0})
+}
+
+func (p *FloatPoint) Equals(f Feature) bool {
+	p2, ok := f.(*FloatPoint)
+	if !ok {
+		return false
+	}
+
+	return p.id == p2.id
+}
+
+func Test_Nearest(t *testing.T) {
+	features := []Feature{}
+	for i := 0; i < 50; i++ {
+		for j := 0; j < 50; j++ {
+			features = append(features, &Point{i, j, fmt.Sprintf("%d-%d", i, j)})
+		}
+	}
+
+	tree := NewRtree(2, 16, features...)
+
+	result, dists := tree.Nearest(NewMbrInt32([]int32{10, 10}, []int32{0, 0}), 5)
+	if len(result) != 5 || len(dists) != 5 {
+		t.Fatalf("Nearest() got %d results, want 5", len(result))
+	}
+
+	if pt := result[0].(*Point); pt.x != 10 || pt.y != 10 || dists[0] != 0 {
+		t.Errorf("Nearest() got wrong first result: %s, dist=%f", pt, dists[0])
+	}
+
+	for i := 1; i < 5; i++ {
+		if dists[i] != 1 {
+			t.Errorf("Nearest() got wrong distance %f at %d", dists[i], i)
+		}
+	}
+}
+
+func Test_Nearest_Float64(t *testing.T) {
+	features := []Feature{}
+	for i := 0; i < 1000; i++ {
+		features = append(features, &FloatPoint{rand.Float64() * 100, rand.Float64() * 100, i})
+	}
+
+	tree := NewRtree(2, 8, features...)
+
+	x, y := rand.Float64()*100, rand.Float64()*100
+	_, dists := tree.Nearest(NewMbrFloat64([]float64{x, y}, []float64{0, 0}), 10)
+
+	expected := make([]float64, len(features))
+	for i, f := range features {
+		p := f.(*FloatPoint)
+		expected[i] = math.Hypot(p.x-x, p.y-y)
+	}
+	sort.Float64s(expected)
+
+	if len(dists) != 10 {
+		t.Fatalf("Nearest() got %d results, want 10", len(dists))
+	}
+
+	for i, d := range dists {
+		if math.Abs(d-expected[i]) > 1e-9 {
+			t.Errorf("Nearest() got distance %f at %d, want %f", d, i, expected[i])
+		}
+	}
+}
+
+func Test_NearestIterator(t *testing.T) {
+	features := []Feature{}
+	for i := 0; i < 500; i++ {
+		features = append(features, &FloatPoint{rand.Float64() * 100, rand.Float64() * 100, i})
+	}
+
+	tree := NewRtree(2, 8, features...)
+	it := tree.NearestIterator(NewMbrFloat64([]float64{40, 40}, []float64{20, 20}))
+
+	count := 0
+	last := -1.0
+	for {
+		f, dist, ok := it.Next()
+		if !ok {
+			break
+		}
+
+		p := f.(*FloatPoint)
+		inside := p.x >= 40 && p.x <= 60 && p.y >= 40 && p.y <= 60
+		if inside != (dist == 0) {
+			t.Errorf("NearestIterator.Next() got wrong distance %f for %v", dist, p)
+		}
+
+		if dist < last {
+			t.Errorf("NearestIterator.Next() got decreasing distance %f after %f", dist, last)
+		}
+
+		last = dist
+		count++
+	}
+
+	if count != len(features) {
+		t.Errorf("NearestIterator visited %d features, want %d", count, len(features))
+	}
+}
+
+func Test_ReverseNearest(t *testing.T) {
+	features := []Feature{}
+	for i := 0; i < 400; i++ {
+		features = append(features, &FloatPoint{rand.Float64() * 100, rand.Float64() * 100, i})
+	}
+
+	tree := NewRtree(2, 8, features...)
+	x, y := 50.0, 50.0
+
+	for _, k := range []int{1, 3} {
+		expected := map[int]bool{}
+		for _, f := range features {
+			p := f.(*FloatPoint)
+			d := math.Hypot(p.x-x, p.y-y)
+
+			closer := 0
+			for _, g := range features {
+				p2 := g.(*FloatPoint)
+				if p2.id != p.id && math.Hypot(p.x-p2.x, p.y-p2.y) < d {
+					closer++
+				}
+			}
+
+			if closer < k {
+				expected[p.id] = true
+			}
+		}
+
+		result := tree.ReverseNearest(NewMbrFloat64([]float64{x, y}, []float64{0, 0}), k)
+		if len(result) != len(expected) {
+			t.Errorf("ReverseNearest() got %d results for k=%d, want %d", len(result), k, len(expected))
+		}
+
+		for _, f := range result {
+			if !expected[f.(*FloatPoint).id] {
+				t.Errorf("ReverseNearest() got wrong result for k=%d: %v", k, f)
+			}
+		}
+	}
+}
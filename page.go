@@ -0,0 +1,100 @@
+package rtree
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidCursor = errors.New("rtree: invalid cursor")
+	ErrInvalidLimit  = errors.New("rtree: invalid limit")
+)
+
+// SearchPage returns at most limit features intersecting mbr, starting
+// where the search identified by cursor stopped, together with the cursor
+// of the next page. An empty cursor starts a new search and an empty next
+// cursor means there are no more results. Cursors stay valid only as long as
+// the tree is not modified. ErrInvalidLimit is returned when limit is not
+// positive.
+func (t *Rtree) SearchPage(mbr Mbr, limit int, cursor string) ([]Feature, string, error) {
+	if limit < 1 {
+		return nil, "", ErrInvalidLimit
+	}
+
+	resume, err := parseCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	results, next := t.searchPage([]Feature{}, t.root, mbr, limit, resume, []int{})
+
+	return results, formatCursor(next), nil
+}
+
+func (t *Rtree) searchPage(results []Feature, n *node, mbr Mbr, limit int, resume, path []int) ([]Feature, []int) {
+	from := 0
+	if len(resume) > 0 {
+		from = resume[0]
+	}
+
+	for i := from; i < len(n.objs); i++ {
+		e := n.objs[i]
+		if !mbr.Intersects(e.mbr) {
+			continue
+		}
+
+		if !n.leaf {
+			// Only the first visited child resumes from the cursor.
+			var sub []int
+			if i == from && len(resume) > 1 {
+				sub = resume[1:]
+			}
+
+			var next []int
+			results, next = t.searchPage(results, e.node, mbr, limit, sub, append(path, i))
+			if next != nil {
+				return results, next
+			}
+			continue
+		}
+
+		if len(results) >= limit {
+			next := make([]int, len(path)+1)
+			copy(next, path)
+			next[len(path)] = i
+			return results, next
+		}
+
+		results = append(results, e.feature)
+	}
+
+	return results, nil
+}
+
+func parseCursor(cursor string) ([]int, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(cursor, ".")
+	path := make([]int, len(parts))
+	for i, part := range parts {
+		index, err := strconv.Atoi(part)
+		if err != nil || index < 0 {
+			return nil, ErrInvalidCursor
+		}
+		path[i] = index
+	}
+
+	return path, nil
+}
+
+func formatCursor(path []int) string {
+	parts := make([]string, len(path))
+	for i, index := range path {
+		parts[i] = strconv.Itoa(index)
+	}
+
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,59 @@
+package rtree
+
+import (
+	"testing"
+)
+
+func Test_SearchPage(t *testing.T) {
+	features := randomRects(3000, 1000, 50)
+	tree := NewRtree(2, 16, features...)
+
+	window := NewMbrInt32([]int32{100, 100}, []int32{600, 600})
+	expected := tree.Search(window)
+
+	seen := map[int]bool{}
+	cursor := ""
+	pages := 0
+	for {
+		page, next, err := tree.SearchPage(window, 100, cursor)
+		if err != nil {
+			t.Fatalf("SearchPage() failed: %s", err)
+		}
+
+		if len(page) > 100 {
+			t.Errorf("SearchPage() got %d results, want at most 100", len(page))
+		}
+
+		for _, f := range page {
+			id := f.(*Rect).id
+			if seen[id] {
+				t.Errorf("SearchPage() got duplicated result: %d", id)
+			}
+			seen[id] = true
+		}
+
+		pages++
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	if len(seen) != len(expected) {
+		t.Errorf("SearchPage() got %d results in total, want %d", len(seen), len(expected))
+	}
+
+	if want := (len(expected) + 99) / 100; pages != want {
+		t.Errorf("SearchPage() got %d pages, want %d", pages, want)
+	}
+
+	if _, _, err := tree.SearchPage(window, 100, "1.x"); err != ErrInvalidCursor {
+		t.Errorf("SearchPage() accepted a malformed cursor")
+	}
+
+	for _, limit := range []int{0, -1} {
+		if _, next, err := tree.SearchPage(window, limit, ""); err != ErrInvalidLimit || next != "" {
+			t.Errorf("SearchPage() accepted limit %d", limit)
+		}
+	}
+}
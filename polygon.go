@@ -0,0 +1,99 @@
+package rtree
+
+// PolygonQuery selects features by a simple 2D polygon over the first two
+// dimensions of their mbrs, it matches nothing in trees of fewer than two
+// dimensions. It prunes nodes disjoint from the polygon and accepts nodes
+// lying inside it as a whole.
+type PolygonQuery struct {
+	points [][2]float64
+	within bool
+}
+
+// IntersectsPolygon returns a query matching features whose mbr intersects
+// the polygon given by its vertices.
+func IntersectsPolygon(points [][2]float64) *PolygonQuery {
+	return &PolygonQuery{
+		points: points,
+	}
+}
+
+// WithinPolygon returns a query matching features whose mbr lies inside the
+// polygon given by its vertices. Mbrs touching the boundary are not within.
+func WithinPolygon(points [][2]float64) *PolygonQuery {
+	return &PolygonQuery{
+		points: points,
+		within: true,
+	}
+}
+
+func (q *PolygonQuery) MatchNode(mbr Mbr) bool {
+	return q.intersects(mbr)
+}
+
+func (q *PolygonQuery) MatchFeature(feature Feature) bool {
+	if q.within {
+		return q.covers(feature.Mbr())
+	}
+
+	return q.intersects(feature.Mbr())
+}
+
+func (q *PolygonQuery) CoversNode(mbr Mbr) bool {
+	return q.covers(mbr)
+}
+
+// intersects reports whether any polygon edge touches mbr, or else whether
+// mbr lies inside the polygon.
+func (q *PolygonQuery) intersects(mbr Mbr) bool {
+	if len(q.points) == 0 || mbr.Dim() < 2 {
+		return false
+	}
+
+	if q.crosses(mbr) {
+		return true
+	}
+
+	return q.containsPoint(mbr.Min(0), mbr.Min(1))
+}
+
+// covers reports whether mbr lies strictly inside the polygon.
+func (q *PolygonQuery) covers(mbr Mbr) bool {
+	if len(q.points) == 0 || mbr.Dim() < 2 {
+		return false
+	}
+
+	return !q.crosses(mbr) && q.containsPoint(mbr.Min(0), mbr.Min(1))
+}
+
+// crosses reports whether any edge of the polygon touches mbr.
+func (q *PolygonQuery) crosses(mbr Mbr) bool {
+	n := len(q.points)
+
+	for i := 0; i < n; i++ {
+		a, b := q.points[i], q.points[(i+1)%n]
+		origin := []float64{a[0], a[1]}
+		dir := []float64{b[0] - a[0], b[1] - a[1]}
+
+		if _, ok := rayIntersect(mbr, origin, dir, 1); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// containsPoint tests (x, y) against the polygon with the even-odd rule.
+func (q *PolygonQuery) containsPoint(x, y float64) bool {
+	inside := false
+	n := len(q.points)
+
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		a, b := q.points[i], q.points[j]
+
+		if (a[1] > y) != (b[1] > y) && x < (b[0]-a[0])*(y-a[1])/(b[1]-a[1])+a[0] {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
@@ -0,0 +1,66 @@
+package rtree
+
+import (
+	"testing"
+)
+
+func Test_PolygonQuery(t *testing.T) {
+	features := []Feature{}
+	for i := 0; i < 100; i++ {
+		for j := 0; j < 100; j++ {
+			features = append(features, &FloatPoint{float64(i) + 0.5, float64(j) + 0.5, i*100 + j})
+		}
+	}
+
+	tree := NewRtree(2, 16, features...)
+
+	// A right triangle with legs of 50 along the axes.
+	triangle := [][2]float64{{0, 0}, {50, 0}, {0, 50}}
+
+	// Points on the hypotenuse intersect the triangle but are not within.
+	onEdge, inside := 0, 0
+	for _, f := range features {
+		p := f.(*FloatPoint)
+		if p.x+p.y < 50 {
+			inside++
+		} else if p.x+p.y == 50 {
+			onEdge++
+		}
+	}
+
+	result := tree.Query(IntersectsPolygon(triangle))
+	if len(result) != inside+onEdge {
+		t.Errorf("Query() got %d results intersecting, want %d", len(result), inside+onEdge)
+	}
+
+	result = tree.Query(WithinPolygon(triangle))
+	if len(result) != inside {
+		t.Errorf("Query() got %d results within, want %d", len(result), inside)
+	}
+
+	for _, f := range result {
+		if p := f.(*FloatPoint); p.x+p.y >= 50 {
+			t.Errorf("Query() got wrong result: %v", p)
+		}
+	}
+
+	rects := randomRects(500, 1000, 100)
+	rectTree := NewRtree(2, 8, rects...)
+	square := [][2]float64{{200, 200}, {600, 200}, {600, 600}, {200, 600}}
+	window := NewMbrInt32([]int32{200, 200}, []int32{400, 400})
+
+	if count, expected := len(rectTree.Query(IntersectsPolygon(square))), len(rectTree.Search(window)); count != expected {
+		t.Errorf("Query() got %d results for a square, want %d", count, expected)
+	}
+}
+
+func Test_PolygonQuery_OneDim(t *testing.T) {
+	square := [][2]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+	mbr := NewMbrFloat64([]float64{2}, []float64{1})
+
+	for _, q := range []*PolygonQuery{IntersectsPolygon(square), WithinPolygon(square)} {
+		if q.MatchNode(mbr) || q.CoversNode(mbr) {
+			t.Errorf("PolygonQuery matched the 1D mbr %s", mbr)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package rtree
+
+// Query is a custom search predicate. MatchNode reports whether a subtree
+// bounded by mbr may hold matching features, so it must never reject an mbr
+// that covers a match. Leaf entries are first tested by MatchNode against the
+// mbr stored on insertion, then MatchFeature decides on the features
+// themselves.
+type Query interface {
+	MatchNode(mbr Mbr) bool
+	MatchFeature(feature Feature) bool
+}
+
+// CoveringQuery is a Query that can also tell when every feature under a
+// node matches, so that whole subtrees are accepted without further tests.
+type CoveringQuery interface {
+	Query
+	CoversNode(mbr Mbr) bool
+}
+
+// Query returns the features matched by q, pruning every subtree whose mbr
+// is rejected by q.MatchNode.
+func (t *Rtree) Query(q Query) []Feature {
+	return t.query([]Feature{}, t.root, q)
+}
+
+func (t *Rtree) query(results []Feature, n *node, q Query) []Feature {
+	cq, covering := q.(CoveringQuery)
+
+	for _, e := range n.objs {
+		if !n.leaf {
+			if covering && cq.CoversNode(e.mbr) {
+				results = t.collect(results, e.node)
+				continue
+			}
+
+			if q.MatchNode(e.mbr) {
+				results = t.query(results, e.node, q)
+			}
+			continue
+		}
+
+		if q.MatchNode(e.mbr) && q.MatchFeature(e.feature) {
+			results = append(results, e.feature)
+		}
+	}
+
+	return results
+}
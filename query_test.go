@@ -0,0 +1,43 @@
+package rtree
+
+import (
+	"testing"
+)
+
+// halfPlaneQuery matches features lying at least partly where x+y <= c.
+type halfPlaneQuery struct {
+	c float64
+}
+
+func (q *halfPlaneQuery) MatchNode(mbr Mbr) bool {
+	return mbr.Min(0)+mbr.Min(1) <= q.c
+}
+
+func (q *halfPlaneQuery) MatchFeature(feature Feature) bool {
+	return q.MatchNode(feature.Mbr())
+}
+
+func Test_Query(t *testing.T) {
+	features := randomRects(2000, 1000, 50)
+	tree := NewRtree(2, 16, features...)
+
+	q := &halfPlaneQuery{c: 600}
+
+	expected := 0
+	for _, f := range features {
+		if q.MatchFeature(f) {
+			expected++
+		}
+	}
+
+	result := tree.Query(q)
+	if len(result) != expected {
+		t.Errorf("Query() got %d results, want %d", len(result), expected)
+	}
+
+	for _, f := range result {
+		if !q.MatchFeature(f) {
+			t.Errorf("Query() got wrong result: %s", f.Mbr())
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package rtree
+
+import (
+	"container/heap"
+	"math"
+)
+
+// RayCast returns the features whose mbr is hit by the ray starting at
+// origin along direction, ordered by entry distance, together with those
+// distances. Only hits within maxDist are reported; pass math.Inf(1) for an
+// unbounded ray. An origin inside an mbr hits it at distance 0. Nothing is
+// hit when origin and direction have different lengths.
+func (t *Rtree) RayCast(origin, direction []float64, maxDist float64) ([]Feature, []float64) {
+	return t.rayCast(origin, direction, maxDist, -1)
+}
+
+// RayCastFirst returns the feature first hit by the ray starting at origin
+// along direction within maxDist, ok is false if nothing is hit.
+func (t *Rtree) RayCastFirst(origin, direction []float64, maxDist float64) (feature Feature, dist float64, ok bool) {
+	features, dists := t.rayCast(origin, direction, maxDist, 1)
+	if len(features) == 0 {
+		return nil, 0, false
+	}
+
+	return features[0], dists[0], true
+}
+
+func (t *Rtree) rayCast(origin, direction []float64, maxDist float64, limit int) ([]Feature, []float64) {
+	features := []Feature{}
+	dists := []float64{}
+
+	if len(direction) != len(origin) {
+		return features, dists
+	}
+
+	// Normalize the direction so that ray parameters are distances.
+	var norm float64
+	for _, d := range direction {
+		norm += d * d
+	}
+	norm = math.Sqrt(norm)
+	if norm == 0 {
+		return features, dists
+	}
+
+	dir := make([]float64, len(direction))
+	for i, d := range direction {
+		dir[i] = d / norm
+	}
+
+	q := &distQueue{}
+	push := func(n *node) {
+		for _, e := range n.objs {
+			if dist, ok := rayIntersect(e.mbr, origin, dir, maxDist); ok {
+				heap.Push(q, &distItem{obj: e, dist: dist})
+			}
+		}
+	}
+
+	push(t.root)
+
+	for q.Len() > 0 && len(features) != limit {
+		item := heap.Pop(q).(*distItem)
+
+		if item.obj.node != nil {
+			push(item.obj.node)
+			continue
+		}
+
+		features = append(features, item.obj.feature)
+		dists = append(dists, item.dist)
+	}
+
+	return features, dists
+}
@@ -0,0 +1,48 @@
+package rtree
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_RayCast(t *testing.T) {
+	features := []Feature{}
+	for i := 0; i < 20; i++ {
+		for j := 0; j < 20; j++ {
+			features = append(features, &Rect{int32(i * 10), int32(j * 10), 5, 5, i*20 + j})
+		}
+	}
+
+	tree := NewRtree(2, 8, features...)
+
+	// A horizontal ray along y=2 crosses the first row of rects.
+	result, dists := tree.RayCast([]float64{-10, 2}, []float64{2, 0}, math.Inf(1))
+	if len(result) != 20 {
+		t.Fatalf("RayCast() got %d results, want 20", len(result))
+	}
+
+	for i, f := range result {
+		r := f.(*Rect)
+		if r.y != 0 || r.x != int32(i*10) || dists[i] != float64(r.x+10) {
+			t.Errorf("RayCast() got wrong result %d: %s at %f", i, r.Mbr(), dists[i])
+		}
+	}
+
+	result, _ = tree.RayCast([]float64{-10, 2}, []float64{1, 0}, 50)
+	if len(result) != 5 {
+		t.Errorf("RayCast() got %d results within max distance, want 5", len(result))
+	}
+
+	f, dist, ok := tree.RayCastFirst([]float64{0, -10}, []float64{1, 1}, math.Inf(1))
+	if !ok || f.(*Rect).id != 20 || math.Abs(dist-math.Sqrt(2)*10) > 1e-9 {
+		t.Errorf("RayCastFirst() got wrong result: %v, %f, %v", f, dist, ok)
+	}
+
+	if _, _, ok := tree.RayCastFirst([]float64{-10, 7}, []float64{1, 0}, math.Inf(1)); ok {
+		t.Errorf("RayCastFirst() hit along an empty row")
+	}
+
+	if result, _ := tree.RayCast([]float64{0, 0}, []float64{1}, math.Inf(1)); len(result) != 0 {
+		t.Errorf("RayCast() got %d results with a mismatched direction, want 0", len(result))
+	}
+}
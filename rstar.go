@@ -0,0 +1,157 @@
+package rtree
+
+import (
+	"math"
+	"sort"
+)
+
+// LeastOverlapEnlargement is the R*-tree ChooseSubtree. When the children
+// are leaves it chooses the child whose overlap with its siblings grows the
+// least, ties being resolved by the least area enlargement, then by the
+// smallest area. Otherwise it behaves as LeastEnlargement.
+type LeastOverlapEnlargement struct{}
+
+func (LeastOverlapEnlargement) Choose(children []Mbr, mbr Mbr, leaves bool) int {
+	if !leaves {
+		return LeastEnlargement{}.Choose(children, mbr, leaves)
+	}
+
+	chosen := 0
+	minOverlap, minEnlargement, minSize := math.MaxFloat64, math.MaxFloat64, math.MaxFloat64
+
+	for i, child := range children {
+		merged := MergeMbrs(child, mbr)
+
+		var overlap float64
+		for j, other := range children {
+			if j == i {
+				continue
+			}
+			overlap += overlapSize(merged, other) - overlapSize(child, other)
+		}
+
+		size := child.size()
+		enlargement := merged.size() - size
+
+		if overlap < minOverlap ||
+			(overlap == minOverlap && enlargement < minEnlargement) ||
+			(overlap == minOverlap && enlargement == minEnlargement && size < minSize) {
+			minOverlap, minEnlargement, minSize = overlap, enlargement, size
+			chosen = i
+		}
+	}
+
+	return chosen
+}
+
+// RStarSplitter is the R*-tree topological split: the split axis minimizes
+// the sum of margins of all candidate distributions, and along it the
+// distribution with the least overlap, then least area, is chosen. As in the
+// R*-tree paper, groups hold at least 40% of the node capacity, or
+// minGroupSize when it is smaller.
+type RStarSplitter struct{}
+
+func (RStarSplitter) Split(mbrs []Mbr, minGroupSize int) (left, right []int) {
+	m := (len(mbrs) - 1) * 2 / 5
+	if minGroupSize < m {
+		m = minGroupSize
+	}
+	if m < 1 {
+		m = 1
+	}
+	if m > len(mbrs)/2 {
+		m = len(mbrs) / 2
+	}
+
+	order := make([]int, len(mbrs))
+	for i := range order {
+		order[i] = i
+	}
+
+	axis, minMargin := 0, math.MaxFloat64
+	for dim := 0; dim < mbrs[0].Dim(); dim++ {
+		var margin float64
+		for _, byMax := range []bool{false, true} {
+			sortByBound(order, mbrs, dim, byMax)
+			for k := m; k <= len(order)-m; k++ {
+				margin += marginOf(mergeIndexes(mbrs, order[:k])) + marginOf(mergeIndexes(mbrs, order[k:]))
+			}
+		}
+
+		if margin < minMargin {
+			axis, minMargin = dim, margin
+		}
+	}
+
+	// The chosen order is kept as scored, sorting again could break ties
+	// differently and shift the distribution.
+	split, best := m, make([]int, len(order))
+	minOverlap, minSize := math.MaxFloat64, math.MaxFloat64
+	for _, byMax := range []bool{false, true} {
+		sortByBound(order, mbrs, axis, byMax)
+		for k := m; k <= len(order)-m; k++ {
+			l, r := mergeIndexes(mbrs, order[:k]), mergeIndexes(mbrs, order[k:])
+			overlap := overlapSize(l, r)
+			size := l.size() + r.size()
+
+			if overlap < minOverlap || (overlap == minOverlap && size < minSize) {
+				minOverlap, minSize = overlap, size
+				split = k
+				copy(best, order)
+			}
+		}
+	}
+
+	return best[:split], best[split:]
+}
+
+// removeFarthest removes the p objects whose centers are the farthest from
+// the center of n and returns them from the closest to the farthest.
+func (n *node) removeFarthest(p int) []*object {
+	if p < 1 {
+		p = 1
+	}
+
+	center := n.computeMbr()
+	dist := func(obj *object) float64 {
+		var sum float64
+		for i := 0; i < center.Dim(); i++ {
+			d := (obj.mbr.Min(i) + obj.mbr.Max(i) - center.Min(i) - center.Max(i)) / 2
+			sum += d * d
+		}
+		return sum
+	}
+
+	sort.SliceStable(n.objs, func(i, j int) bool {
+		return dist(n.objs[i]) > dist(n.objs[j])
+	})
+
+	removed := make([]*object, p)
+	for i := range removed {
+		removed[i] = n.objs[p-1-i]
+	}
+	n.objs = append([]*object{}, n.objs[p:]...)
+
+	return removed
+}
+
+func mergeIndexes(mbrs []Mbr, indexes []int) Mbr {
+	merged := make([]Mbr, len(indexes))
+	for i, index := range indexes {
+		merged[i] = mbrs[index]
+	}
+
+	return MergeMbrs(merged...)
+}
+
+// sortByBound sorts indexes of mbrs by the lower, or upper, bound of the
+// mbrs along dim.
+func sortByBound(indexes []int, mbrs []Mbr, dim int, byMax bool) {
+	sort.SliceStable(indexes, func(i, j int) bool {
+		a, b := mbrs[indexes[i]], mbrs[indexes[j]]
+		if byMax {
+			return a.Max(dim) < b.Max(dim)
+		}
+		return a.Min(dim) < b.Min(dim)
+	})
+}
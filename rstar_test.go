@@ -0,0 +1,172 @@
+package rtree
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// checkTree verifies the structure of a tree: parent links, levels, mbrs
+// and subtree counts.
+func checkTree(t *testing.T, tree *Rtree) {
+	var check func(n *node) int
+	check = func(n *node) int {
+		if n.leaf {
+			if n.count != len(n.objs) {
+				t.Errorf("leaf count is %d, want %d", n.count, len(n.objs))
+			}
+			return len(n.objs)
+		}
+
+		count := 0
+		for _, e := range n.objs {
+			if e.node.parent != n {
+				t.Errorf("wrong parent link at level %d", n.level)
+			}
+			if e.node.level != n.level-1 {
+				t.Errorf("child level is %d under level %d", e.node.level, n.level)
+			}
+			if !e.mbr.Equals(e.node.computeMbr()) {
+				t.Errorf("stale mbr %s at level %d", e.mbr, n.level)
+			}
+			count += check(e.node)
+		}
+
+		if n.count != count {
+			t.Errorf("node count is %d, want %d", n.count, count)
+		}
+		return count
+	}
+
+	if count := check(tree.root); count != int(tree.Size()) {
+		t.Errorf("tree holds %d features, want %d", count, tree.Size())
+	}
+}
+
+func Test_RStar(t *testing.T) {
+	tree := NewRtree(2, 8)
+	tree.SetStrategy(StrategyRStar)
+
+	features := randomRects(3000, 1000, 30)
+	for _, f := range features {
+		tree.Insert(f)
+	}
+
+	checkTree(t, tree)
+
+	for k := 0; k < 100; k++ {
+		window := NewMbrInt32([]int32{rand.Int31n(1000), rand.Int31n(1000)}, []int32{rand.Int31n(200), rand.Int31n(200)})
+
+		expected := 0
+		for _, f := range features {
+			if window.Intersects(f.Mbr()) {
+				expected++
+			}
+		}
+
+		if count := len(tree.Search(window)); count != expected {
+			t.Errorf("Search() got %d results, want %d", count, expected)
+			break
+		}
+	}
+
+	for _, f := range features[:1500] {
+		if !tree.Remove(f) {
+			t.Errorf("Remove() failed for %s", f.Mbr())
+		}
+	}
+
+	checkTree(t, tree)
+
+	if count := len(tree.Search(NewMbrInt32([]int32{0, 0}, []int32{2000, 2000}))); count != 1500 {
+		t.Errorf("Search() got %d results after removal, want 1500", count)
+	}
+}
+
+func Test_RStarSplitter_Ties(t *testing.T) {
+	const n, m = 9, 3
+
+	// Rects sharing many bounds, on which sorting by a bound again could
+	// break ties differently and shift the chosen distribution.
+	fixed := [][4]int32{
+		{1, 1, 2, 0}, {2, 1, 1, 2}, {1, 1, 2, 0}, {1, 0, 0, 0}, {2, 2, 0, 1},
+		{1, 1, 0, 2}, {3, 3, 1, 1}, {3, 1, 1, 0}, {2, 3, 1, 1},
+	}
+	mbrs := make([]Mbr, n)
+	for i, r := range fixed {
+		mbrs[i] = NewMbrInt32([]int32{r[0], r[1]}, []int32{r[2], r[3]})
+	}
+	checkRStarSplit(t, mbrs, m)
+
+	for trial := 0; trial < 2000; trial++ {
+		// Two columns of integer rects on one row, touching at x=3 and
+		// sharing many bounds, so a split without overlap exists.
+		size := m + rand.Intn(n-2*m+1)
+		mbrs := make([]Mbr, n)
+		for i := range mbrs {
+			x := rand.Int31n(3)
+			if i >= size {
+				x = 3
+			}
+			mbrs[i] = NewMbrInt32([]int32{x, 0}, []int32{3 - x%3, 1})
+		}
+		rand.Shuffle(n, func(i, j int) {
+			mbrs[i], mbrs[j] = mbrs[j], mbrs[i]
+		})
+
+		checkRStarSplit(t, mbrs, m)
+	}
+}
+
+// checkRStarSplit checks that the R* split of mbrs is a partition in groups
+// of at least m, with the least overlap among all legal distributions.
+func checkRStarSplit(t *testing.T, mbrs []Mbr, m int) {
+	t.Helper()
+	n := len(mbrs)
+
+	minOverlap := math.MaxFloat64
+	for mask := 1; mask < 1<<n-1; mask++ {
+		l, r := []int{}, []int{}
+		for i := 0; i < n; i++ {
+			if mask&(1<<i) != 0 {
+				l = append(l, i)
+			} else {
+				r = append(r, i)
+			}
+		}
+		if len(l) < m || len(r) < m {
+			continue
+		}
+		minOverlap = math.Min(minOverlap, overlapSize(mergeIndexes(mbrs, l), mergeIndexes(mbrs, r)))
+	}
+
+	left, right := RStarSplitter{}.Split(mbrs, m)
+
+	seen := map[int]bool{}
+	for _, i := range append(append([]int{}, left...), right...) {
+		seen[i] = true
+	}
+	if len(seen) != n || len(left)+len(right) != n || len(left) < m || len(right) < m {
+		t.Fatalf("Split() got groups %v and %v, want a partition of %d in groups of at least %d", left, right, n, m)
+	}
+
+	if o := overlapSize(mergeIndexes(mbrs, left), mergeIndexes(mbrs, right)); o != minOverlap {
+		t.Fatalf("Split() got overlap %f, want %f for %v", o, minOverlap, mbrs)
+	}
+}
+
+func Test_ForcedReinsert(t *testing.T) {
+	tree := NewRtree(2, 8)
+	tree.SetForcedReinsert(true)
+
+	features := randomRects(2000, 1000, 30)
+	for _, f := range features {
+		tree.Insert(f)
+	}
+
+	checkTree(t, tree)
+
+	if count := len(tree.Search(NewMbrInt32([]int32{0, 0}, []int32{2000, 2000}))); count != len(features) {
+		t.Errorf("Search() got %d results, want %d", count, len(features))
+	}
+}
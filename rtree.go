@@ -1,6 +1,7 @@
 package rtree
 
 import (
+	"fmt"
 	"math"
 	"sort"
 )
@@ -10,6 +11,16 @@ type Feature interface {
 	Equals(f Feature) bool
 }
 
+// Strategy is a preset of the algorithms used to insert features and split
+// nodes.
+type Strategy int
+
+const (
+	StrategyQuadratic Strategy = iota
+	StrategyRStar
+	StrategyLinear
+)
+
 type Rtree struct {
 	dim     int
 	fan     int
@@ -18,6 +29,20 @@ type Rtree struct {
 	root   *node
 	size   int32
 	height int8
+
+	splitter       Splitter
+	chooser        ChooseSubtree
+	forcedReinsert bool
+
+	// reinserted records the levels which already had a forced reinsertion
+	// during the current insertion, pending holds the objects to reinsert.
+	reinserted map[int8]bool
+	pending    []pendingObj
+}
+
+type pendingObj struct {
+	obj   *object
+	level int8
 }
 
 func NewRtree(dim int, fan int, features ...Feature) *Rtree {
@@ -34,6 +59,9 @@ func NewRtree(dim int, fan int, features ...Feature) *Rtree {
 
 		size:   0,
 		height: 1,
+
+		splitter: QuadraticSplitter{},
+		chooser:  LeastEnlargement{},
 	}
 
 	if len(features) <= fan {
@@ -59,14 +87,56 @@ func (t *Rtree) Height() int8 {
 	return t.height
 }
 
+// SetStrategy selects the insertion and split algorithms used by later
+// insertions, the default is StrategyQuadratic. It panics on an unknown
+// strategy.
+func (t *Rtree) SetStrategy(strategy Strategy) {
+	switch strategy {
+	case StrategyQuadratic:
+		t.splitter, t.chooser, t.forcedReinsert = QuadraticSplitter{}, LeastEnlargement{}, false
+	case StrategyRStar:
+		t.splitter, t.chooser, t.forcedReinsert = RStarSplitter{}, LeastOverlapEnlargement{}, true
+	case StrategyLinear:
+		t.splitter, t.chooser, t.forcedReinsert = LinearSplitter{}, LeastEnlargement{}, false
+	default:
+		panic(fmt.Sprintf("rtree: unknown strategy %d", strategy))
+	}
+}
+
+// SetSplitter replaces the algorithm used to split overflowing nodes. Forced
+// reinsertion is left as it is, see SetForcedReinsert.
+func (t *Rtree) SetSplitter(splitter Splitter) {
+	t.splitter = splitter
+}
+
+// SetChooseSubtree replaces the algorithm used to choose the subtree an
+// inserted feature goes into. Forced reinsertion is left as it is, see
+// SetForcedReinsert.
+func (t *Rtree) SetChooseSubtree(chooser ChooseSubtree) {
+	t.chooser = chooser
+}
+
+// SetForcedReinsert enables or disables the R*-tree forced reinsertion, where
+// the first overflow of a level during an insertion reinserts the 30% of
+// entries farthest from the node center instead of splitting it. It is
+// enabled by StrategyRStar and disabled by the other strategies.
+func (t *Rtree) SetForcedReinsert(reinsert bool) {
+	t.forcedReinsert = reinsert
+}
+
 func (t *Rtree) Insert(feature Feature) {
 	obj := &object{
 		mbr:     feature.Mbr(),
 		feature: feature,
 	}
 
+	if t.forcedReinsert {
+		t.reinserted = map[int8]bool{}
+	}
+
 	t.insertObj(obj, 1)
 
+	t.reinserted = nil
 	t.size++
 }
 
@@ -80,7 +150,7 @@ func (t *Rtree) insertObj(e *object, level int8) {
 
 	var split *node
 	if len(leaf.objs) > t.fan {
-		leaf, split = leaf.split(t.halfFan)
+		leaf, split = t.overflow(leaf)
 	}
 
 	root, splitRoot := t.adjustTree(leaf, split)
@@ -102,9 +172,65 @@ func (t *Rtree) insertObj(e *object, level int8) {
 			},
 		}
 
+		t.root.count = oldRoot.count + splitRoot.count
+
 		oldRoot.parent = t.root
 		splitRoot.parent = t.root
 	}
+
+	for len(t.pending) > 0 {
+		p := t.pending[0]
+		t.pending = t.pending[1:]
+		t.insertObj(p.obj, p.level)
+	}
+}
+
+// overflow handles a node holding more than fan objects, either by splitting
+// it or, with forced reinsertion enabled, by reinserting some of its objects
+// the first time a level overflows during an insertion.
+func (t *Rtree) overflow(n *node) (*node, *node) {
+	if t.reinserted != nil && n != t.root && !t.reinserted[n.level] {
+		t.reinserted[n.level] = true
+
+		for _, obj := range n.removeFarthest(len(n.objs) * 3 / 10) {
+			t.pending = append(t.pending, pendingObj{obj, n.level})
+		}
+
+		return n, nil
+	}
+
+	return t.split(n)
+}
+
+// split distributes the objects of n between n and a new sibling as decided
+// by the splitter.
+func (t *Rtree) split(n *node) (left, right *node) {
+	objs := n.objs
+	mbrs := make([]Mbr, len(objs))
+	for i, obj := range objs {
+		mbrs[i] = obj.mbr
+	}
+
+	leftGroup, rightGroup := t.splitter.Split(mbrs, t.halfFan)
+	checkSplit(len(mbrs), leftGroup, rightGroup)
+
+	left = n
+	left.objs = make([]*object, 0, len(leftGroup))
+	right = &node{
+		parent: n.parent,
+		leaf:   n.leaf,
+		level:  n.level,
+		objs:   make([]*object, 0, len(rightGroup)),
+	}
+
+	for _, i := range leftGroup {
+		assign(objs[i], left)
+	}
+	for _, i := range rightGroup {
+		assign(objs[i], right)
+	}
+
+	return
 }
 
 func (t *Rtree) bulkLoad(features []Feature) {
@@ -138,6 +264,8 @@ func (t *Rtree) bulkLoad(features []Feature) {
 			node: node,
 		}
 	}
+
+	t.root.count = t.root.computeCount()
 }
 
 func (t *Rtree) omt(level int8, objs []*object, m int) *node {
@@ -146,6 +274,7 @@ func (t *Rtree) omt(level int8, objs []*object, m int) *node {
 			leaf:  true,
 			objs:  objs,
 			level: level,
+			count: len(objs),
 		}
 	}
 
@@ -166,6 +295,8 @@ func (t *Rtree) omt(level int8, objs []*object, m int) *node {
 		})
 	}
 
+	n.count = n.computeCount()
+
 	return n
 }
 
@@ -174,21 +305,27 @@ func (t *Rtree) chooseNode(n *node, obj *object, level int8) *node {
 		return n
 	}
 
-	diff := math.MaxFloat64
-	var chosen *object
-	for _, en := range n.objs {
-		mbr := MergeMbrs(en.mbr, obj.mbr)
-		d := mbr.size() - en.mbr.size()
-		if d < diff || (d == diff && en.mbr.size() < chosen.mbr.size()) {
-			diff = d
-			chosen = en
-		}
+	children := make([]Mbr, len(n.objs))
+	for i, en := range n.objs {
+		children[i] = en.mbr
+	}
+
+	i := t.chooser.Choose(children, obj.mbr, n.objs[0].node.leaf)
+	if i < 0 || i >= len(children) {
+		panic(fmt.Sprintf("rtree: ChooseSubtree returned index %d out of %d children", i, len(children)))
 	}
 
+	chosen := n.objs[i]
+
 	return t.chooseNode(chosen.node, obj, level)
 }
 
 func (t *Rtree) adjustTree(n, nn *node) (*node, *node) {
+	n.count = n.computeCount()
+	if nn != nil {
+		nn.count = nn.computeCount()
+	}
+
 	if n == t.root {
 		return n, nn
 	}
@@ -208,7 +345,7 @@ func (t *Rtree) adjustTree(n, nn *node) (*node, *node) {
 	n.parent.objs = append(n.parent.objs, enn)
 
 	if len(n.parent.objs) > t.fan {
-		return t.adjustTree(n.parent.split(t.halfFan))
+		return t.adjustTree(t.overflow(n.parent))
 	}
 
 	return t.adjustTree(n.parent, nil)
@@ -235,6 +372,208 @@ func (t *Rtree) searchIntersect(results []Feature, n *node, mbr Mbr) []Feature {
 	return results
 }
 
+// SearchMany answers several window searches in a single traversal. The
+// i-th result holds the features intersecting mbrs[i].
+func (t *Rtree) SearchMany(mbrs []Mbr) [][]Feature {
+	s := &manySearch{
+		mbrs:    mbrs,
+		results: make([][]Feature, len(mbrs)),
+	}
+
+	active := make([]int, len(mbrs))
+	for i := range mbrs {
+		s.results[i] = []Feature{}
+		active[i] = i
+	}
+
+	s.search(t.root, active, 0)
+
+	return s.results
+}
+
+type manySearch struct {
+	mbrs    []Mbr
+	results [][]Feature
+
+	// hits holds one scratch slice per depth for the windows carried down.
+	hits [][]int
+}
+
+func (s *manySearch) search(n *node, active []int, depth int) {
+	if depth == len(s.hits) {
+		s.hits = append(s.hits, make([]int, 0, len(s.mbrs)))
+	}
+
+	for _, e := range n.objs {
+		// Only windows intersecting this entry are carried down.
+		hits := s.hits[depth][:0]
+		for _, i := range active {
+			if s.mbrs[i].Intersects(e.mbr) {
+				hits = append(hits, i)
+			}
+		}
+
+		if len(hits) == 0 {
+			continue
+		}
+
+		if !n.leaf {
+			s.search(e.node, hits, depth+1)
+			continue
+		}
+
+		for _, i := range hits {
+			s.results[i] = append(s.results[i], e.feature)
+		}
+	}
+}
+
+// SearchFunc calls fn for every feature intersecting mbr without building a
+// result slice. Traversal stops as soon as fn returns false.
+func (t *Rtree) SearchFunc(mbr Mbr, fn func(Feature) bool) {
+	t.searchFunc(t.root, mbr, fn)
+}
+
+func (t *Rtree) searchFunc(n *node, mbr Mbr, fn func(Feature) bool) bool {
+	for _, e := range n.objs {
+		if !mbr.Intersects(e.mbr) {
+			continue
+		}
+
+		if !n.leaf {
+			if !t.searchFunc(e.node, mbr, fn) {
+				return false
+			}
+			continue
+		}
+
+		if !fn(e.feature) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Count returns the number of features intersecting mbr without collecting
+// them. Subtrees fully covered by mbr are counted without being visited.
+func (t *Rtree) Count(mbr Mbr) int {
+	return t.count(t.root, mbr)
+}
+
+func (t *Rtree) count(n *node, mbr Mbr) int {
+	count := 0
+
+	for _, e := range n.objs {
+		if !mbr.Intersects(e.mbr) {
+			continue
+		}
+
+		if !n.leaf {
+			if mbr.Contains(e.mbr) {
+				count += e.node.count
+				continue
+			}
+
+			count += t.count(e.node, mbr)
+			continue
+		}
+
+		count++
+	}
+
+	return count
+}
+
+// SearchWithin returns features whose mbr lies within radius of center,
+// measured as the minimum Euclidean distance between the two mbrs.
+func (t *Rtree) SearchWithin(center Mbr, radius float64) []Feature {
+	return t.searchWithin([]Feature{}, t.root, center, radius)
+}
+
+func (t *Rtree) searchWithin(results []Feature, n *node, center Mbr, radius float64) []Feature {
+	for _, e := range n.objs {
+		if minDist(center, e.mbr) > radius {
+			continue
+		}
+
+		if !n.leaf {
+			results = t.searchWithin(results, e.node, center, radius)
+			continue
+		}
+
+		results = append(results, e.feature)
+	}
+
+	return results
+}
+
+// SearchContained returns features whose mbr is fully inside mbr.
+func (t *Rtree) SearchContained(mbr Mbr) []Feature {
+	return t.searchContained([]Feature{}, t.root, mbr)
+}
+
+func (t *Rtree) searchContained(results []Feature, n *node, mbr Mbr) []Feature {
+	for _, e := range n.objs {
+		if !mbr.Intersects(e.mbr) {
+			continue
+		}
+
+		if !n.leaf {
+			if mbr.Contains(e.mbr) {
+				results = t.collect(results, e.node)
+				continue
+			}
+
+			results = t.searchContained(results, e.node, mbr)
+			continue
+		}
+
+		if mbr.Contains(e.mbr) {
+			results = append(results, e.feature)
+		}
+	}
+
+	return results
+}
+
+// SearchContaining returns features whose mbr fully covers mbr.
+func (t *Rtree) SearchContaining(mbr Mbr) []Feature {
+	return t.searchContaining([]Feature{}, t.root, mbr)
+}
+
+func (t *Rtree) searchContaining(results []Feature, n *node, mbr Mbr) []Feature {
+	for _, e := range n.objs {
+		// A node can only hold a covering feature if it covers mbr itself.
+		if !e.mbr.Contains(mbr) {
+			continue
+		}
+
+		if !n.leaf {
+			results = t.searchContaining(results, e.node, mbr)
+			continue
+		}
+
+		results = append(results, e.feature)
+	}
+
+	return results
+}
+
+// collect appends every feature under n to results.
+func (t *Rtree) collect(results []Feature, n *node) []Feature {
+	for _, e := range n.objs {
+		if !n.leaf {
+			results = t.collect(results, e.node)
+			continue
+		}
+
+		results = append(results, e.feature)
+	}
+
+	return results
+}
+
 func (t *Rtree) Remove(feature Feature) bool {
 	n := t.findLeaf(t.root, feature)
 	if n == nil {
@@ -292,6 +631,8 @@ func (t *Rtree) condenseTree(n *node) {
 	deleted := []*node{}
 
 	for n != t.root {
+		n.count = n.computeCount()
+
 		if len(n.objs) < t.halfFan {
 			objs := []*object{}
 			for _, obj := range n.parent.objs {
@@ -312,6 +653,8 @@ func (t *Rtree) condenseTree(n *node) {
 		n = n.parent
 	}
 
+	t.root.count = t.root.computeCount()
+
 	for _, node := range deleted {
 		obj := &object{
 			mbr:     node.computeMbr(),
@@ -328,6 +671,9 @@ type node struct {
 	leaf   bool
 	objs   []*object
 	level  int8
+
+	// count is the number of features in the subtree rooted at this node.
+	count int
 }
 
 func (n *node) getObject() *object {
@@ -352,77 +698,17 @@ func (n *node) computeMbr() Mbr {
 	return MergeMbrs(mbrs...)
 }
 
-func (n *node) split(minGroupSize int) (left, right *node) {
-	l, r := n.pickSeeds()
-	leftSeed, rightSeed := n.objs[l], n.objs[r]
-
-	remaining := append(n.objs[:l], n.objs[l+1:r]...)
-	remaining = append(remaining, n.objs[r+1:]...)
-
-	left = n
-	left.objs = []*object{leftSeed}
-	right = &node{
-		parent: n.parent,
-		leaf:   n.leaf,
-		level:  n.level,
-		objs:   []*object{rightSeed},
-	}
-
-	// TODO
-	if rightSeed.node != nil {
-		rightSeed.node.parent = right
-	}
-	if leftSeed.node != nil {
-		leftSeed.node.parent = left
+func (n *node) computeCount() int {
+	if n.leaf {
+		return len(n.objs)
 	}
 
-	for len(remaining) > 0 {
-		next := pickNext(left, right, remaining)
-		e := remaining[next]
-
-		if len(remaining)+len(left.objs) <= minGroupSize {
-			assign(e, left)
-		} else if len(remaining)+len(right.objs) <= minGroupSize {
-			assign(e, right)
-		} else {
-			assignGroup(e, left, right)
-		}
-
-		remaining = append(remaining[:next], remaining[next+1:]...)
+	count := 0
+	for _, obj := range n.objs {
+		count += obj.node.count
 	}
 
-	return
-}
-
-func (n *node) pickSeeds() (int, int) {
-	left, right := 0, 1
-	maxWastedSpace := -1.0
-	for i, obj1 := range n.objs {
-		for j, obj2 := range n.objs[i+1:] {
-			d := MergeMbrs(obj1.mbr, obj2.mbr).size() - obj1.mbr.size() - obj2.mbr.size()
-			if d > maxWastedSpace {
-				maxWastedSpace = d
-				left, right = i, j+i+1
-			}
-		}
-	}
-	return left, right
-}
-
-func pickNext(left *node, right *node, objs []*object) (next int) {
-	maxDiff := -1.0
-	leftMbr := left.computeMbr()
-	rightMbr := right.computeMbr()
-	for i, obj := range objs {
-		d1 := MergeMbrs(leftMbr, obj.mbr).size() - leftMbr.size()
-		d2 := MergeMbrs(rightMbr, obj.mbr).size() - rightMbr.size()
-		d := math.Abs(d1 - d2)
-		if d > maxDiff {
-			maxDiff = d
-			next = i
-		}
-	}
-	return
+	return count
 }
 
 type object struct {
@@ -518,34 +804,3 @@ func assign(obj *object, group *node) {
 
 	group.objs = append(group.objs, obj)
 }
-
-func assignGroup(obj *object, left, right *node) {
-	leftMbr := left.computeMbr()
-	rightMbr := right.computeMbr()
-	leftEnlarged := MergeMbrs(leftMbr, obj.mbr)
-	rightEnlarged := MergeMbrs(rightMbr, obj.mbr)
-
-	leftDiff := leftEnlarged.size() - leftMbr.size()
-	rightDiff := rightEnlarged.size() - rightMbr.size()
-	if diff := leftDiff - rightDiff; diff < 0 {
-		assign(obj, left)
-		return
-	} else if diff > 0 {
-		assign(obj, right)
-		return
-	}
-
-	if diff := leftMbr.size() - rightMbr.size(); diff < 0 {
-		assign(obj, left)
-		return
-	} else if diff > 0 {
-		assign(obj, right)
-		return
-	}
-
-	if diff := len(left.objs) - len(right.objs); diff <= 0 {
-		assign(obj, left)
-		return
-	}
-	assign(obj, right)
-}
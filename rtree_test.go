@@ -134,3 +134,228 @@ func Test_Search_1M(t *testing.T) {
 
 	t.Logf("Tree height = %d", tree.Height())
 }
+
+func Test_SearchWithin(t *testing.T) {
+	features := []Feature{}
+	for i := 0; i < 100; i++ {
+		for j := 0; j < 100; j++ {
+			features = append(features, &Point{i, j, fmt.Sprintf("%d-%d", i, j)})
+		}
+	}
+
+	tree := NewRtree(2, 16, features...)
+
+	result := tree.SearchWithin(NewMbrInt32([]int32{50, 50}, []int32{0, 0}), 5)
+
+	expected := 0
+	for _, f := range features {
+		p := f.(*Point)
+		if (p.x-50)*(p.x-50)+(p.y-50)*(p.y-50) <= 25 {
+			expected++
+		}
+	}
+
+	if len(result) != expected {
+		t.Errorf("SearchWithin() got %d results, want %d", len(result), expected)
+	}
+
+	for _, f := range result {
+		p := f.(*Point)
+		if (p.x-50)*(p.x-50)+(p.y-50)*(p.y-50) > 25 {
+			t.Errorf("SearchWithin() got wrong result: %s", p)
+		}
+	}
+}
+
+type Rect struct {
+	x, y, w, h int32
+	id         int
+}
+
+func (r *Rect) Mbr() Mbr {
+	return NewMbrInt32([]int32{r.x, r.y}, []int32{r.w, r.h})
+}
+
+func (r *Rect) Equals(f Feature) bool {
+	r2, ok := f.(*Rect)
+	if !ok {
+		return false
+	}
+
+	return r.id == r2.id
+}
+
+func randomRects(n int, extent, maxSize int32) []Feature {
+	features := make([]Feature, n)
+	for i := range features {
+		features[i] = &Rect{
+			rand.Int31n(extent), rand.Int31n(extent),
+			rand.Int31n(maxSize), rand.Int31n(maxSize),
+			i,
+		}
+	}
+	return features
+}
+
+func Test_SearchContained_SearchContaining(t *testing.T) {
+	features := randomRects(2000, 1000, 100)
+	tree := NewRtree(2, 16, features...)
+
+	window := NewMbrInt32([]int32{200, 200}, []int32{300, 300})
+	point := NewMbrInt32([]int32{500, 500}, []int32{0, 0})
+
+	contained, containing := 0, 0
+	for _, f := range features {
+		if window.Contains(f.Mbr()) {
+			contained++
+		}
+		if f.Mbr().Contains(point) {
+			containing++
+		}
+	}
+
+	result := tree.SearchContained(window)
+	if len(result) != contained {
+		t.Errorf("SearchContained() got %d results, want %d", len(result), contained)
+	}
+	for _, f := range result {
+		if !window.Contains(f.Mbr()) {
+			t.Errorf("SearchContained() got wrong result: %s", f.Mbr())
+		}
+	}
+
+	result = tree.SearchContaining(point)
+	if len(result) != containing {
+		t.Errorf("SearchContaining() got %d results, want %d", len(result), containing)
+	}
+	for _, f := range result {
+		if !f.Mbr().Contains(point) {
+			t.Errorf("SearchContaining() got wrong result: %s", f.Mbr())
+		}
+	}
+}
+
+func Test_SearchFunc(t *testing.T) {
+	features := randomRects(2000, 1000, 50)
+	tree := NewRtree(2, 16, features...)
+
+	window := NewMbrInt32([]int32{100, 100}, []int32{500, 500})
+
+	count := 0
+	tree.SearchFunc(window, func(f Feature) bool {
+		if !window.Intersects(f.Mbr()) {
+			t.Errorf("SearchFunc() got wrong result: %s", f.Mbr())
+		}
+		count++
+		return true
+	})
+
+	if expected := len(tree.Search(window)); count != expected {
+		t.Errorf("SearchFunc() visited %d features, want %d", count, expected)
+	}
+
+	count = 0
+	tree.SearchFunc(window, func(f Feature) bool {
+		count++
+		return count < 3
+	})
+
+	if count != 3 {
+		t.Errorf("SearchFunc() did not stop early, visited %d features", count)
+	}
+}
+
+func Test_Count(t *testing.T) {
+	features := randomRects(3000, 1000, 50)
+
+	loaded := NewRtree(2, 16, features...)
+	if count := loaded.Count(NewMbrInt32([]int32{0, 0}, []int32{2000, 2000})); count != len(features) {
+		t.Errorf("Count() got %d for the whole tree, want %d", count, len(features))
+	}
+
+	tree := NewRtree(2, 16)
+	for _, f := range features {
+		tree.Insert(f)
+	}
+
+	for _, f := range features[:1000] {
+		tree.Remove(f)
+	}
+
+	if count := tree.Count(NewMbrInt32([]int32{0, 0}, []int32{2000, 2000})); count != int(tree.Size()) {
+		t.Errorf("Count() got %d for the whole tree, want %d", count, tree.Size())
+	}
+
+	for k := 0; k < 100; k++ {
+		window := NewMbrInt32([]int32{rand.Int31n(1000), rand.Int31n(1000)}, []int32{rand.Int31n(300), rand.Int31n(300)})
+
+		if count, expected := tree.Count(window), len(tree.Search(window)); count != expected {
+			t.Errorf("Count() got %d, want %d", count, expected)
+			break
+		}
+
+		if count, expected := loaded.Count(window), len(loaded.Search(window)); count != expected {
+			t.Errorf("Count() got %d, want %d", count, expected)
+			break
+		}
+	}
+}
+
+func Test_SearchMany(t *testing.T) {
+	features := randomRects(2000, 1000, 50)
+	tree := NewRtree(2, 16, features...)
+
+	mbrs := make([]Mbr, 50)
+	for i := range mbrs {
+		mbrs[i] = NewMbrInt32([]int32{rand.Int31n(1000), rand.Int31n(1000)}, []int32{rand.Int31n(100), rand.Int31n(100)})
+	}
+
+	results := tree.SearchMany(mbrs)
+	if len(results) != len(mbrs) {
+		t.Fatalf("SearchMany() got %d results, want %d", len(results), len(mbrs))
+	}
+
+	for i, mbr := range mbrs {
+		if count, expected := len(results[i]), len(tree.Search(mbr)); count != expected {
+			t.Errorf("SearchMany() got %d features for %s, want %d", count, mbr, expected)
+		}
+	}
+}
+
+func Test_LinearSplit(t *testing.T) {
+	tree := NewRtree(2, 64)
+	tree.SetStrategy(StrategyLinear)
+
+	features := randomRects(5000, 1000, 30)
+	for _, f := range features {
+		tree.Insert(f)
+	}
+
+	checkTree(t, tree)
+
+	for k := 0; k < 100; k++ {
+		window := NewMbrInt32([]int32{rand.Int31n(1000), rand.Int31n(1000)}, []int32{rand.Int31n(200), rand.Int31n(200)})
+
+		expected := 0
+		for _, f := range features {
+			if window.Intersects(f.Mbr()) {
+				expected++
+			}
+		}
+
+		if count := len(tree.Search(window)); count != expected {
+			t.Errorf("Search() got %d results, want %d", count, expected)
+			break
+		}
+	}
+}
+
+func Test_SetStrategy_Unknown(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("SetStrategy() accepted an unknown strategy")
+		}
+	}()
+
+	NewRtree(2, 16).SetStrategy(StrategyLinear + 1)
+}
@@ -0,0 +1,84 @@
+package rtree
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// Sample returns n features chosen uniformly at random, without
+// replacement, among those intersecting mbr, in tree order. All of them are
+// returned when fewer than n intersect. Subtrees fully covered by mbr are
+// skipped as a whole when none of their features is drawn. A nil rng draws
+// from the default source of math/rand.
+func (t *Rtree) Sample(mbr Mbr, n int, rng *rand.Rand) []Feature {
+	if n <= 0 {
+		return []Feature{}
+	}
+
+	intn := rand.Intn
+	if rng != nil {
+		intn = rng.Intn
+	}
+
+	total := t.Count(mbr)
+	if n > total {
+		n = total
+	}
+
+	// Draw n distinct ranks among the matches with Floyd's algorithm.
+	drawn := make(map[int]bool, n)
+	ranks := make([]int, 0, n)
+	for j := total - n; j < total; j++ {
+		r := intn(j + 1)
+		if drawn[r] {
+			r = j
+		}
+		drawn[r] = true
+		ranks = append(ranks, r)
+	}
+	sort.Ints(ranks)
+
+	s := &sampler{
+		mbr:     mbr,
+		ranks:   ranks,
+		results: make([]Feature, 0, n),
+	}
+	s.sample(t.root)
+
+	return s.results
+}
+
+type sampler struct {
+	mbr     Mbr
+	ranks   []int
+	offset  int
+	results []Feature
+}
+
+func (s *sampler) sample(n *node) {
+	for _, e := range n.objs {
+		if len(s.ranks) == 0 {
+			return
+		}
+
+		if !s.mbr.Intersects(e.mbr) {
+			continue
+		}
+
+		if !n.leaf {
+			if s.mbr.Contains(e.mbr) && s.ranks[0] >= s.offset+e.node.count {
+				s.offset += e.node.count
+				continue
+			}
+
+			s.sample(e.node)
+			continue
+		}
+
+		if s.ranks[0] == s.offset {
+			s.results = append(s.results, e.feature)
+			s.ranks = s.ranks[1:]
+		}
+		s.offset++
+	}
+}
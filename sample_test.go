@@ -0,0 +1,64 @@
+package rtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func Test_Sample(t *testing.T) {
+	features := randomRects(5000, 1000, 20)
+	tree := NewRtree(2, 16, features...)
+	rng := rand.New(rand.NewSource(1))
+
+	window := NewMbrInt32([]int32{100, 100}, []int32{500, 500})
+	total := len(tree.Search(window))
+
+	result := tree.Sample(window, 100, rng)
+	if len(result) != 100 {
+		t.Fatalf("Sample() got %d results, want 100", len(result))
+	}
+
+	seen := map[int]bool{}
+	for _, f := range result {
+		if !window.Intersects(f.Mbr()) {
+			t.Errorf("Sample() got wrong result: %s", f.Mbr())
+		}
+
+		id := f.(*Rect).id
+		if seen[id] {
+			t.Errorf("Sample() got duplicated result: %d", id)
+		}
+		seen[id] = true
+	}
+
+	if result := tree.Sample(window, total+10, rng); len(result) != total {
+		t.Errorf("Sample() got %d results, want all %d", len(result), total)
+	}
+
+	if result := tree.Sample(window, 10, nil); len(result) != 10 {
+		t.Errorf("Sample() got %d results with a nil rng, want 10", len(result))
+	}
+
+	for _, n := range []int{0, -1} {
+		if result := tree.Sample(window, n, rng); len(result) != 0 {
+			t.Errorf("Sample() got %d results for n=%d, want 0", len(result), n)
+		}
+	}
+
+	// Every match should be drawn about as often as the others.
+	small := NewMbrInt32([]int32{0, 0}, []int32{100, 100})
+	matches := tree.Search(small)
+	hits := map[int]int{}
+	for k := 0; k < 2000; k++ {
+		for _, f := range tree.Sample(small, 1, rng) {
+			hits[f.(*Rect).id]++
+		}
+	}
+
+	mean := 2000 / len(matches)
+	for _, f := range matches {
+		if h := hits[f.(*Rect).id]; h == 0 || h > mean*4 {
+			t.Errorf("Sample() drew feature %d %d times, expected about %d", f.(*Rect).id, h, mean)
+		}
+	}
+}
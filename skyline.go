@@ -0,0 +1,83 @@
+package rtree
+
+import (
+	"container/heap"
+)
+
+// Skyline returns the features not dominated by any other, comparing the
+// lower corners of their mbrs where smaller is better in every dimension.
+// A feature dominates another when it is no worse in all dimensions and
+// better in at least one. When window is not nil only features intersecting
+// it are considered.
+func (t *Rtree) Skyline(window Mbr) []Feature {
+	skyline := []Feature{}
+	corners := []Mbr{}
+
+	dominated := func(m Mbr) bool {
+		for _, c := range corners {
+			if dominates(c, m) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Entries are browsed by increasing sum of their lower corner, so that
+	// no entry can be dominated by one popped after it.
+	q := &distQueue{}
+	push := func(n *node) {
+		for _, e := range n.objs {
+			if window != nil && !window.Intersects(e.mbr) {
+				continue
+			}
+
+			if dominated(e.mbr) {
+				continue
+			}
+
+			var sum float64
+			for i := 0; i < e.mbr.Dim(); i++ {
+				sum += e.mbr.Min(i)
+			}
+
+			heap.Push(q, &distItem{obj: e, dist: sum})
+		}
+	}
+
+	push(t.root)
+
+	for q.Len() > 0 {
+		item := heap.Pop(q).(*distItem)
+
+		if dominated(item.obj.mbr) {
+			continue
+		}
+
+		if item.obj.node != nil {
+			push(item.obj.node)
+			continue
+		}
+
+		skyline = append(skyline, item.obj.feature)
+		corners = append(corners, item.obj.mbr)
+	}
+
+	return skyline
+}
+
+// dominates reports whether the lower corner of a dominates the lower corner
+// of b.
+func dominates(a, b Mbr) bool {
+	better := false
+
+	for i := 0; i < a.Dim() && i < b.Dim(); i++ {
+		if a.Min(i) > b.Min(i) {
+			return false
+		}
+		if a.Min(i) < b.Min(i) {
+			better = true
+		}
+	}
+
+	return better
+}
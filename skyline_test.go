@@ -0,0 +1,49 @@
+package rtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func Test_Skyline(t *testing.T) {
+	features := []Feature{}
+	for i := 0; i < 2000; i++ {
+		features = append(features, &FloatPoint{rand.Float64() * 100, rand.Float64() * 100, i})
+	}
+
+	tree := NewRtree(2, 16, features...)
+
+	for _, window := range []Mbr{nil, NewMbrFloat64([]float64{30, 30}, []float64{50, 50})} {
+		candidates := features
+		if window != nil {
+			candidates = tree.Search(window)
+		}
+
+		expected := map[int]bool{}
+		for _, f := range candidates {
+			p := f.(*FloatPoint)
+			skyline := true
+			for _, g := range candidates {
+				p2 := g.(*FloatPoint)
+				if p2.x <= p.x && p2.y <= p.y && (p2.x < p.x || p2.y < p.y) {
+					skyline = false
+					break
+				}
+			}
+			if skyline {
+				expected[p.id] = true
+			}
+		}
+
+		result := tree.Skyline(window)
+		if len(result) != len(expected) {
+			t.Errorf("Skyline() got %d results, want %d", len(result), len(expected))
+		}
+
+		for _, f := range result {
+			if !expected[f.(*FloatPoint).id] {
+				t.Errorf("Skyline() got wrong result: %v", f)
+			}
+		}
+	}
+}
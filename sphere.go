@@ -0,0 +1,48 @@
+package rtree
+
+// SphereQuery selects features by a hypersphere, typically over a tree of
+// MbrFloat64. Mbrs are tested exactly against the sphere: a node is pruned
+// when its nearest point is out of the sphere and accepted as a whole when
+// its farthest corner is inside.
+type SphereQuery struct {
+	center []float64
+	radius float64
+	within bool
+}
+
+// IntersectsSphere returns a query matching features whose mbr intersects
+// the sphere of radius around center.
+func IntersectsSphere(center []float64, radius float64) *SphereQuery {
+	return &SphereQuery{
+		center: center,
+		radius: radius,
+	}
+}
+
+// WithinSphere returns a query matching features whose mbr lies inside the
+// sphere of radius around center.
+func WithinSphere(center []float64, radius float64) *SphereQuery {
+	return &SphereQuery{
+		center: center,
+		radius: radius,
+		within: true,
+	}
+}
+
+func (q *SphereQuery) MatchNode(mbr Mbr) bool {
+	near, _ := pointDists(mbr, q.center)
+	return near <= q.radius
+}
+
+func (q *SphereQuery) MatchFeature(feature Feature) bool {
+	if q.within {
+		return q.CoversNode(feature.Mbr())
+	}
+
+	return q.MatchNode(feature.Mbr())
+}
+
+func (q *SphereQuery) CoversNode(mbr Mbr) bool {
+	_, far := pointDists(mbr, q.center)
+	return far <= q.radius
+}
@@ -0,0 +1,70 @@
+package rtree
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+type Box struct {
+	mins  []float64
+	spans []float64
+	id    int
+}
+
+func (b *Box) Mbr() Mbr {
+	return NewMbrFloat64(b.mins, b.spans)
+}
+
+func (b *Box) Equals(f Feature) bool {
+	b2, ok := f.(*Box)
+	if !ok {
+		return false
+	}
+
+	return b.id == b2.id
+}
+
+func Test_SphereQuery(t *testing.T) {
+	features := make([]Feature, 3000)
+	for i := range features {
+		features[i] = &Box{
+			[]float64{rand.Float64() * 100, rand.Float64() * 100, rand.Float64() * 100},
+			[]float64{rand.Float64() * 5, rand.Float64() * 5, rand.Float64() * 5},
+			i,
+		}
+	}
+
+	tree := NewRtree(3, 16, features...)
+	center := []float64{50, 50, 50}
+	radius := 20.0
+
+	intersecting, within := 0, 0
+	for _, f := range features {
+		b := f.(*Box)
+
+		var near, far float64
+		for i, c := range center {
+			lo, hi := b.mins[i], b.mins[i]+b.spans[i]
+			d := math.Max(math.Max(lo-c, c-hi), 0)
+			near += d * d
+			d = math.Max(math.Abs(c-lo), math.Abs(hi-c))
+			far += d * d
+		}
+
+		if math.Sqrt(near) <= radius {
+			intersecting++
+		}
+		if math.Sqrt(far) <= radius {
+			within++
+		}
+	}
+
+	if count := len(tree.Query(IntersectsSphere(center, radius))); count != intersecting {
+		t.Errorf("Query() got %d results intersecting, want %d", count, intersecting)
+	}
+
+	if count := len(tree.Query(WithinSphere(center, radius))); count != within {
+		t.Errorf("Query() got %d results within, want %d", count, within)
+	}
+}
@@ -0,0 +1,219 @@
+package rtree
+
+import (
+	"fmt"
+	"math"
+)
+
+// Splitter distributes the mbrs of an overflowing node in two groups and
+// returns the indexes of the mbrs in each group. Every index must be in
+// exactly one group, and both groups should hold at least minGroupSize mbrs.
+// The tree panics on groups which are empty or do not cover every index
+// exactly once.
+type Splitter interface {
+	Split(mbrs []Mbr, minGroupSize int) (left, right []int)
+}
+
+// ChooseSubtree picks among the mbrs of the children of a node the one which
+// receives mbr on insertion, and returns its index. The tree panics on an
+// index out of range. leaves tells whether the children are leaf nodes.
+type ChooseSubtree interface {
+	Choose(children []Mbr, mbr Mbr, leaves bool) int
+}
+
+// QuadraticSplitter is Guttman's quadratic split.
+type QuadraticSplitter struct{}
+
+func (QuadraticSplitter) Split(mbrs []Mbr, minGroupSize int) (left, right []int) {
+	l, r := pickSeeds(mbrs)
+	return distribute(mbrs, l, r, minGroupSize, pickNext)
+}
+
+// LinearSplitter is Guttman's linear split, seeds are picked in linear time
+// and the remaining mbrs are assigned in order.
+type LinearSplitter struct{}
+
+func (LinearSplitter) Split(mbrs []Mbr, minGroupSize int) (left, right []int) {
+	l, r := linearPickSeeds(mbrs)
+	return distribute(mbrs, l, r, minGroupSize, pickFirst)
+}
+
+// LeastEnlargement chooses the child needing the least area enlargement,
+// then the smallest one.
+type LeastEnlargement struct{}
+
+func (LeastEnlargement) Choose(children []Mbr, mbr Mbr, leaves bool) int {
+	chosen := 0
+	diff := math.MaxFloat64
+	for i, child := range children {
+		d := MergeMbrs(child, mbr).size() - child.size()
+		if d < diff || (d == diff && child.size() < children[chosen].size()) {
+			diff = d
+			chosen = i
+		}
+	}
+
+	return chosen
+}
+
+// checkSplit panics unless left and right are non-empty and together hold
+// every index below n exactly once.
+func checkSplit(n int, left, right []int) {
+	if len(left) == 0 || len(right) == 0 {
+		panic(fmt.Sprintf("rtree: Splitter returned an empty group, %d and %d of %d", len(left), len(right), n))
+	}
+
+	seen := make([]bool, n)
+	for _, group := range [][]int{left, right} {
+		for _, i := range group {
+			if i < 0 || i >= n {
+				panic(fmt.Sprintf("rtree: Splitter returned index %d out of %d", i, n))
+			}
+			if seen[i] {
+				panic(fmt.Sprintf("rtree: Splitter returned index %d twice", i))
+			}
+			seen[i] = true
+		}
+	}
+
+	if len(left)+len(right) != n {
+		panic(fmt.Sprintf("rtree: Splitter returned %d of %d indexes", len(left)+len(right), n))
+	}
+}
+
+type splitGroup struct {
+	mbr     Mbr
+	indexes []int
+}
+
+func (g *splitGroup) add(i int, mbr Mbr) {
+	g.indexes = append(g.indexes, i)
+	g.mbr = MergeMbrs(g.mbr, mbr)
+}
+
+// distribute splits mbrs in two groups seeded with the mbrs at l and r,
+// then assigns the mbrs chosen one by one by next.
+func distribute(mbrs []Mbr, l, r int, minGroupSize int, next func(left, right *splitGroup, mbrs []Mbr, remaining []int) int) ([]int, []int) {
+	left := &splitGroup{mbr: mbrs[l], indexes: []int{l}}
+	right := &splitGroup{mbr: mbrs[r], indexes: []int{r}}
+
+	remaining := make([]int, 0, len(mbrs)-2)
+	for i := range mbrs {
+		if i != l && i != r {
+			remaining = append(remaining, i)
+		}
+	}
+
+	for len(remaining) > 0 {
+		k := next(left, right, mbrs, remaining)
+		i := remaining[k]
+
+		if len(remaining)+len(left.indexes) <= minGroupSize {
+			left.add(i, mbrs[i])
+		} else if len(remaining)+len(right.indexes) <= minGroupSize {
+			right.add(i, mbrs[i])
+		} else {
+			assignGroup(i, mbrs[i], left, right)
+		}
+
+		remaining = append(remaining[:k], remaining[k+1:]...)
+	}
+
+	return left.indexes, right.indexes
+}
+
+func pickSeeds(mbrs []Mbr) (int, int) {
+	left, right := 0, 1
+	maxWastedSpace := -1.0
+	for i, mbr1 := range mbrs {
+		for j, mbr2 := range mbrs[i+1:] {
+			d := MergeMbrs(mbr1, mbr2).size() - mbr1.size() - mbr2.size()
+			if d > maxWastedSpace {
+				maxWastedSpace = d
+				left, right = i, j+i+1
+			}
+		}
+	}
+	return left, right
+}
+
+// linearPickSeeds picks the pair of mbrs with the greatest separation along
+// any dimension, normalized by the width of all mbrs on it.
+func linearPickSeeds(mbrs []Mbr) (int, int) {
+	left, right := 0, 1
+	maxSeparation := math.Inf(-1)
+	for dim := 0; dim < mbrs[0].Dim(); dim++ {
+		highestLow, lowestHigh := 0, 0
+		minLow, maxHigh := math.Inf(1), math.Inf(-1)
+		for i, mbr := range mbrs {
+			if mbr.Min(dim) > mbrs[highestLow].Min(dim) {
+				highestLow = i
+			}
+			if mbr.Max(dim) < mbrs[lowestHigh].Max(dim) {
+				lowestHigh = i
+			}
+			minLow = math.Min(minLow, mbr.Min(dim))
+			maxHigh = math.Max(maxHigh, mbr.Max(dim))
+		}
+
+		if highestLow == lowestHigh {
+			continue
+		}
+
+		separation := mbrs[highestLow].Min(dim) - mbrs[lowestHigh].Max(dim)
+		if width := maxHigh - minLow; width > 0 {
+			separation /= width
+		}
+
+		if separation > maxSeparation {
+			maxSeparation = separation
+			left, right = lowestHigh, highestLow
+		}
+	}
+
+	return left, right
+}
+
+func pickFirst(left, right *splitGroup, mbrs []Mbr, remaining []int) int {
+	return 0
+}
+
+func pickNext(left, right *splitGroup, mbrs []Mbr, remaining []int) (next int) {
+	maxDiff := -1.0
+	for k, i := range remaining {
+		d1 := MergeMbrs(left.mbr, mbrs[i]).size() - left.mbr.size()
+		d2 := MergeMbrs(right.mbr, mbrs[i]).size() - right.mbr.size()
+		d := math.Abs(d1 - d2)
+		if d > maxDiff {
+			maxDiff = d
+			next = k
+		}
+	}
+	return
+}
+
+func assignGroup(i int, mbr Mbr, left, right *splitGroup) {
+	leftDiff := MergeMbrs(left.mbr, mbr).size() - left.mbr.size()
+	rightDiff := MergeMbrs(right.mbr, mbr).size() - right.mbr.size()
+	if diff := leftDiff - rightDiff; diff < 0 {
+		left.add(i, mbr)
+		return
+	} else if diff > 0 {
+		right.add(i, mbr)
+		return
+	}
+
+	if diff := left.mbr.size() - right.mbr.size(); diff < 0 {
+		left.add(i, mbr)
+		return
+	} else if diff > 0 {
+		right.add(i, mbr)
+		return
+	}
+
+	if diff := len(left.indexes) - len(right.indexes); diff <= 0 {
+		left.add(i, mbr)
+		return
+	}
+	right.add(i, mbr)
+}
@@ -0,0 +1,120 @@
+package rtree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// medianSplitter splits at the median of the lower bounds along one axis.
+type medianSplitter struct {
+	dim   int
+	calls int
+}
+
+func (s *medianSplitter) Split(mbrs []Mbr, minGroupSize int) (left, right []int) {
+	s.calls++
+
+	order := make([]int, len(mbrs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return mbrs[order[i]].Min(s.dim) < mbrs[order[j]].Min(s.dim)
+	})
+
+	return order[:len(order)/2], order[len(order)/2:]
+}
+
+// firstChooser always descends into the first child.
+type firstChooser struct {
+	calls int
+}
+
+func (c *firstChooser) Choose(children []Mbr, mbr Mbr, leaves bool) int {
+	c.calls++
+	return 0
+}
+
+func Test_Splitter_ChooseSubtree(t *testing.T) {
+	splitter := &medianSplitter{dim: 1}
+	chooser := &firstChooser{}
+
+	tree := NewRtree(2, 8)
+	tree.SetSplitter(splitter)
+	tree.SetChooseSubtree(chooser)
+
+	features := randomRects(2000, 1000, 20)
+	for _, f := range features {
+		tree.Insert(f)
+	}
+
+	if splitter.calls == 0 || chooser.calls == 0 {
+		t.Errorf("custom algorithms not used: %d splits, %d choices", splitter.calls, chooser.calls)
+	}
+
+	checkTree(t, tree)
+
+	window := NewMbrInt32([]int32{rand.Int31n(800), rand.Int31n(800)}, []int32{200, 200})
+	expected := 0
+	for _, f := range features {
+		if window.Intersects(f.Mbr()) {
+			expected++
+		}
+	}
+
+	if count := len(tree.Search(window)); count != expected {
+		t.Errorf("Search() got %d results, want %d", count, expected)
+	}
+}
+
+// badSplitter returns fixed, broken groups.
+type badSplitter struct {
+	left, right []int
+}
+
+func (s *badSplitter) Split(mbrs []Mbr, minGroupSize int) (left, right []int) {
+	return s.left, s.right
+}
+
+type badChooser struct{}
+
+func (badChooser) Choose(children []Mbr, mbr Mbr, leaves bool) int {
+	return len(children)
+}
+
+func Test_Splitter_ChooseSubtree_Invalid(t *testing.T) {
+	insert := func(tree *Rtree) (panicked bool) {
+		defer func() {
+			panicked = recover() != nil
+		}()
+
+		for _, f := range randomRects(100, 1000, 20) {
+			tree.Insert(f)
+		}
+		return false
+	}
+
+	splitters := []*badSplitter{
+		{[]int{}, []int{0, 1, 2, 3, 4}},
+		{[]int{0, 1}, []int{2, 3}},
+		{[]int{0, 1, 2}, []int{2, 3, 4}},
+		{[]int{0, 1}, []int{2, 3, 5}},
+	}
+
+	for _, s := range splitters {
+		tree := NewRtree(2, 4)
+		tree.SetSplitter(s)
+
+		if !insert(tree) {
+			t.Errorf("Insert() accepted groups %v and %v", s.left, s.right)
+		}
+	}
+
+	tree := NewRtree(2, 4)
+	tree.SetChooseSubtree(badChooser{})
+
+	if !insert(tree) {
+		t.Errorf("Insert() accepted a child index out of range")
+	}
+}
@@ -0,0 +1,120 @@
+package rtree
+
+import (
+	"math"
+	"sort"
+)
+
+// NewRtreeSTR creates a tree bulk loaded with Sort-Tile-Recursive packing.
+// Features are sorted into slabs along every dimension in turn, which yields
+// nearly full nodes with little overlap. Such trees suit static data but
+// still accept later insertions and removals.
+func NewRtreeSTR(dim int, fan int, features ...Feature) *Rtree {
+	t := NewRtree(dim, fan)
+
+	if len(features) > 0 {
+		t.strLoad(features)
+	}
+
+	return t
+}
+
+func (t *Rtree) strLoad(features []Feature) {
+	objs := make([]*object, len(features))
+	for i, feature := range features {
+		objs[i] = &object{
+			mbr:     feature.Mbr(),
+			feature: feature,
+		}
+	}
+
+	level := int8(1)
+	nodes := t.strPack(objs, level, true)
+
+	for len(nodes) > 1 {
+		objs = make([]*object, len(nodes))
+		for i, n := range nodes {
+			objs[i] = &object{
+				mbr:  n.computeMbr(),
+				node: n,
+			}
+		}
+
+		level++
+		nodes = t.strPack(objs, level, false)
+	}
+
+	t.root = nodes[0]
+	t.height = level
+	t.size = int32(len(features))
+}
+
+// strPack packs objects into nodes of the given level.
+func (t *Rtree) strPack(objs []*object, level int8, leaf bool) []*node {
+	groups := t.strTile(objs, 0)
+
+	nodes := make([]*node, len(groups))
+	for i, group := range groups {
+		n := &node{
+			leaf:  leaf,
+			level: level,
+			objs:  group,
+		}
+
+		for _, obj := range group {
+			if obj.node != nil {
+				obj.node.parent = n
+			}
+		}
+
+		n.count = n.computeCount()
+		nodes[i] = n
+	}
+
+	return nodes
+}
+
+// strTile sorts objects by their centers along dim and cuts them into slabs,
+// which are tiled along the next dimensions. Along the last dimension slabs
+// are cut into groups of fan objects.
+func (t *Rtree) strTile(objs []*object, dim int) [][]*object {
+	sortByCenter(dim, objs)
+
+	if dim >= t.dim-1 {
+		return splitBySize(t.fan, objs)
+	}
+
+	pages := int(math.Ceil(float64(len(objs)) / float64(t.fan)))
+	slabs := int(math.Ceil(math.Pow(float64(pages), 1/float64(t.dim-dim))))
+	slabSize := t.fan * int(math.Ceil(float64(pages)/float64(slabs)))
+
+	groups := [][]*object{}
+	for _, slab := range splitBySize(slabSize, objs) {
+		groups = append(groups, t.strTile(slab, dim+1)...)
+	}
+
+	return groups
+}
+
+// splitBySize splits objects into slices of size objects, the last one
+// holding the remainder.
+func splitBySize(size int, objs []*object) [][]*object {
+	split := [][]*object{}
+	for i := 0; i < len(objs); i += size {
+		end := i + size
+		if end > len(objs) {
+			end = len(objs)
+		}
+
+		split = append(split, objs[i:end:end])
+	}
+
+	return split
+}
+
+func sortByCenter(dim int, objs []*object) {
+	sort.Slice(objs, func(i, j int) bool {
+		a, b := objs[i].mbr, objs[j].mbr
+		return a.Min(dim)+a.Max(dim) < b.Min(dim)+b.Max(dim)
+	})
+}
@@ -0,0 +1,59 @@
+package rtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func Test_NewRtreeSTR(t *testing.T) {
+	features := randomRects(10000, 1000, 20)
+	tree := NewRtreeSTR(2, 16, features...)
+
+	if tree.Size() != int32(len(features)) {
+		t.Errorf("Size() got %d, want %d", tree.Size(), len(features))
+	}
+
+	checkTree(t, tree)
+
+	// Leaves are full but for the last one of each slab.
+	leaves := 0
+	var countLeaves func(n *node)
+	countLeaves = func(n *node) {
+		if n.leaf {
+			leaves++
+			return
+		}
+		for _, e := range n.objs {
+			countLeaves(e.node)
+		}
+	}
+	countLeaves(tree.root)
+
+	if limit := len(features)/16 + 26; leaves > limit {
+		t.Errorf("tree has %d leaves, want at most %d", leaves, limit)
+	}
+
+	more := randomRects(1000, 1000, 20)
+	for _, f := range more {
+		tree.Insert(f)
+	}
+	features = append(features, more...)
+
+	checkTree(t, tree)
+
+	for k := 0; k < 100; k++ {
+		window := NewMbrInt32([]int32{rand.Int31n(1000), rand.Int31n(1000)}, []int32{rand.Int31n(200), rand.Int31n(200)})
+
+		expected := 0
+		for _, f := range features {
+			if window.Intersects(f.Mbr()) {
+				expected++
+			}
+		}
+
+		if count := len(tree.Search(window)); count != expected {
+			t.Errorf("Search() got %d results, want %d", count, expected)
+			break
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package rtree
+
+import (
+	"container/heap"
+)
+
+// TopK returns the k highest scoring features intersecting mbr, ordered by
+// decreasing score, together with their scores. bound must return an upper
+// bound of the scores of all features under a node with the given mbr, so
+// that subtrees which cannot beat the current results are never visited.
+func (t *Rtree) TopK(mbr Mbr, k int, score func(Feature) float64, bound func(Mbr) float64) ([]Feature, []float64) {
+	features := []Feature{}
+	scores := []float64{}
+
+	// Priorities are negated scores, so the min-heap pops the best first.
+	q := &distQueue{}
+	push := func(n *node) {
+		for _, e := range n.objs {
+			if !mbr.Intersects(e.mbr) {
+				continue
+			}
+
+			if n.leaf {
+				heap.Push(q, &distItem{obj: e, dist: -score(e.feature)})
+			} else {
+				heap.Push(q, &distItem{obj: e, dist: -bound(e.mbr)})
+			}
+		}
+	}
+
+	push(t.root)
+
+	for q.Len() > 0 && len(features) < k {
+		item := heap.Pop(q).(*distItem)
+
+		if item.obj.node != nil {
+			push(item.obj.node)
+			continue
+		}
+
+		features = append(features, item.obj.feature)
+		scores = append(scores, -item.dist)
+	}
+
+	return features, scores
+}
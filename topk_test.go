@@ -0,0 +1,39 @@
+package rtree
+
+import (
+	"sort"
+	"testing"
+)
+
+func Test_TopK(t *testing.T) {
+	features := randomRects(3000, 1000, 20)
+	tree := NewRtree(2, 16, features...)
+
+	// Features score higher the further they extend to the right, which is
+	// bounded by the right edge of their node.
+	score := func(f Feature) float64 {
+		return f.Mbr().Max(0)
+	}
+	bound := func(mbr Mbr) float64 {
+		return mbr.Max(0)
+	}
+
+	window := NewMbrInt32([]int32{200, 200}, []int32{400, 400})
+
+	expected := []float64{}
+	for _, f := range tree.Search(window) {
+		expected = append(expected, score(f))
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(expected)))
+
+	result, scores := tree.TopK(window, 10, score, bound)
+	if len(result) != 10 {
+		t.Fatalf("TopK() got %d results, want 10", len(result))
+	}
+
+	for i, s := range scores {
+		if s != expected[i] || score(result[i]) != s {
+			t.Errorf("TopK() got score %f at %d, want %f", s, i, expected[i])
+		}
+	}
+}